@@ -0,0 +1,132 @@
+package visisql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/huandu/go-sqlbuilder"
+)
+
+var errInvalidIdentifier = errors.New("identifier must only contain letters, digits and underscores, and must not start with a digit")
+var errCommonTableExpressionQuery = errors.New("common table expression must have a query")
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type CommonTableExpression struct {
+	Name      string       `json:"name"`
+	Columns   []string     `json:"columns,omitempty"`
+	Query     *SelectQuery `json:"query"`
+	Recursive *SelectQuery `json:"recursive,omitempty"`
+}
+
+func NewCommonTableExpression(name string, query *SelectQuery) *CommonTableExpression {
+	return &CommonTableExpression{Name: name, Query: query}
+}
+
+func NewRecursiveCommonTableExpression(name string, columns []string, query *SelectQuery, recursive *SelectQuery) *CommonTableExpression {
+	return &CommonTableExpression{Name: name, Columns: columns, Query: query, Recursive: recursive}
+}
+
+func (cte *CommonTableExpression) IsRecursive() bool {
+	return cte.Recursive != nil
+}
+
+func (cte *CommonTableExpression) clone() *CommonTableExpression {
+	c := &CommonTableExpression{
+		Name:    cte.Name,
+		Columns: append([]string(nil), cte.Columns...),
+	}
+
+	if cte.Query != nil {
+		c.Query = cte.Query.Clone()
+	}
+
+	if cte.Recursive != nil {
+		c.Recursive = cte.Recursive.Clone()
+	}
+
+	return c
+}
+
+func validateIdentifier(ident string) error {
+	if !identifierRegexp.MatchString(ident) {
+		return fmt.Errorf("%w, got %q", errInvalidIdentifier, ident)
+	}
+
+	return nil
+}
+
+func withCommonTableExpressions(ctes []*CommonTableExpression, builder sqlbuilder.Builder, cfg *buildConfig) (sqlbuilder.Builder, error) {
+	if len(ctes) == 0 {
+		return builder, nil
+	}
+
+	args := &sqlbuilder.Args{Flavor: sqlbuilder.PostgreSQL}
+
+	recursive := false
+	exprs := make([]string, 0, len(ctes))
+	for _, cte := range ctes {
+		if err := validateIdentifier(cte.Name); err != nil {
+			return nil, fmt.Errorf("visisql common table expression: %w", &QueryError{err})
+		}
+
+		for _, c := range cte.Columns {
+			if err := validateIdentifier(c); err != nil {
+				return nil, fmt.Errorf("visisql common table expression: %w", &QueryError{err})
+			}
+		}
+
+		if cte.Query == nil {
+			return nil, fmt.Errorf("visisql common table expression: %w", &QueryError{errCommonTableExpressionQuery})
+		}
+
+		builderQ, err := newQueryBuilder(cte.Query, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		body := args.Add(builderQ)
+
+		if cte.IsRecursive() {
+			recursive = true
+
+			builderR, err := newQueryBuilder(cte.Recursive, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			body = fmt.Sprintf("%s UNION ALL %s", body, args.Add(builderR))
+		}
+
+		name := cte.Name
+		if len(cte.Columns) > 0 {
+			name = fmt.Sprintf("%s (%s)", name, strings.Join(cte.Columns, ", "))
+		}
+
+		exprs = append(exprs, fmt.Sprintf("%s AS (%s)", name, body))
+	}
+
+	with := "WITH"
+	if recursive {
+		with = "WITH RECURSIVE"
+	}
+
+	format := fmt.Sprintf("%s %s %s", with, strings.Join(exprs, ", "), args.Add(builder))
+
+	return &commonTableExpressionBuilder{args: args, format: format}, nil
+}
+
+type commonTableExpressionBuilder struct {
+	args   *sqlbuilder.Args
+	format string
+}
+
+func (b *commonTableExpressionBuilder) Build() (string, []interface{}) {
+	return b.args.Compile(b.format)
+}
+
+func (b *commonTableExpressionBuilder) BuildWithFlavor(flavor sqlbuilder.Flavor, initialArg ...interface{}) (string, []interface{}) {
+	return b.args.CompileWithFlavor(b.format, flavor, initialArg...)
+}
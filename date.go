@@ -0,0 +1,82 @@
+package visisql
+
+import (
+	"errors"
+	"time"
+)
+
+var errOperatorTimeRange = errors.New("predicate must have two values when operator is time range")
+var errDatePartLocation = errors.New("date part location must have a name known by PostgreSQL, not Local")
+
+var now = time.Now
+
+type DatePart string
+
+const (
+	DatePartYear      DatePart = "year"
+	DatePartQuarter   DatePart = "quarter"
+	DatePartMonth     DatePart = "month"
+	DatePartWeek      DatePart = "week"
+	DatePartDay       DatePart = "day"
+	DatePartDayOfWeek DatePart = "isodow"
+	DatePartHour      DatePart = "hour"
+)
+
+// NewTimeRangePredicate matches field in the half-open range [from, to).
+func NewTimeRangePredicate(field string, from time.Time, to time.Time) *Predicate {
+	return &Predicate{Field: field, Operator: OperatorTimeRange, Values: []interface{}{from, to}}
+}
+
+// NewDayPredicate matches field during the calendar day of t in loc, UTC if loc is nil.
+func NewDayPredicate(field string, t time.Time, loc *time.Location) *Predicate {
+	start := startOfDay(t, loc)
+	return NewTimeRangePredicate(field, start, start.AddDate(0, 0, 1))
+}
+
+func NewTodayPredicate(field string, loc *time.Location) *Predicate {
+	return NewDayPredicate(field, now(), loc)
+}
+
+// NewLastDaysPredicate matches field during the last n calendar days in loc, today included.
+func NewLastDaysPredicate(field string, n int, loc *time.Location) *Predicate {
+	end := startOfDay(now(), loc).AddDate(0, 0, 1)
+	return NewTimeRangePredicate(field, end.AddDate(0, 0, -n), end)
+}
+
+// NewMonthPredicate matches field during the calendar month in loc, UTC if loc is nil.
+func NewMonthPredicate(field string, year int, month time.Month, loc *time.Location) *Predicate {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, location(loc))
+	return NewTimeRangePredicate(field, start, start.AddDate(0, 1, 0))
+}
+
+func NewThisMonthPredicate(field string, loc *time.Location) *Predicate {
+	t := now().In(location(loc))
+	return NewMonthPredicate(field, t.Year(), t.Month(), loc)
+}
+
+// NewDatePartPredicate compares the part of field, taken in loc, with value.
+// Location must have a name known by PostgreSQL, UTC if loc is nil, so time.Local is rejected.
+func NewDatePartPredicate(field string, part DatePart, operator Operator, value int, loc *time.Location) (*Predicate, error) {
+	name := location(loc).String()
+	if name == "Local" {
+		return nil, errDatePartLocation
+	}
+
+	return NewPredicate(field, operator, []interface{}{value}).WithFuncs(
+		NewFunc("timezone", FuncSideField, name).WithOperandAt(1),
+		NewFunc("date_part", FuncSideField, string(part)).WithOperandAt(1),
+	), nil
+}
+
+func location(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(location(loc))
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,77 @@
+package visisql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatePredicates(t *testing.T) {
+	type in struct {
+		predicate *Predicate
+	}
+
+	type out struct {
+		res []interface{}
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	now = func() time.Time { return time.Date(2024, 3, 31, 23, 30, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	var tests = []*test{{
+		message: "today in utc",
+		in:      &in{predicate: NewTodayPredicate("c.created_at", nil)},
+		out:     &out{res: []interface{}{time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)}},
+	}, {
+		message: "today in location",
+		in:      &in{predicate: NewTodayPredicate("c.created_at", paris)},
+		out:     &out{res: []interface{}{time.Date(2024, 4, 1, 0, 0, 0, 0, paris), time.Date(2024, 4, 2, 0, 0, 0, 0, paris)}},
+	}, {
+		message: "day across daylight saving time",
+		in:      &in{predicate: NewDayPredicate("c.created_at", time.Date(2024, 3, 31, 12, 0, 0, 0, paris), paris)},
+		out:     &out{res: []interface{}{time.Date(2024, 3, 31, 0, 0, 0, 0, paris), time.Date(2024, 4, 1, 0, 0, 0, 0, paris)}},
+	}, {
+		message: "last days",
+		in:      &in{predicate: NewLastDaysPredicate("c.created_at", 7, paris)},
+		out:     &out{res: []interface{}{time.Date(2024, 3, 26, 0, 0, 0, 0, paris), time.Date(2024, 4, 2, 0, 0, 0, 0, paris)}},
+	}, {
+		message: "this month",
+		in:      &in{predicate: NewThisMonthPredicate("c.created_at", paris)},
+		out:     &out{res: []interface{}{time.Date(2024, 4, 1, 0, 0, 0, 0, paris), time.Date(2024, 5, 1, 0, 0, 0, 0, paris)}},
+	}, {
+		message: "december",
+		in:      &in{predicate: NewMonthPredicate("c.created_at", 2023, time.December, nil)},
+		out:     &out{res: []interface{}{time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}},
+	}}
+
+	for _, test := range tests {
+		assert.Equal(t, OperatorTimeRange, test.in.predicate.Operator, test.message)
+		assert.Len(t, test.in.predicate.Values, len(test.out.res), test.message)
+		for i, v := range test.in.predicate.Values {
+			assert.True(t, test.out.res[i].(time.Time).Equal(v.(time.Time)), test.message)
+			assert.Equal(t, test.out.res[i].(time.Time).Location(), v.(time.Time).Location(), test.message)
+		}
+	}
+}
+
+func TestDatePartPredicate(t *testing.T) {
+	p, err := NewDatePartPredicate("c.created_at", DatePartHour, OperatorEqual, 9, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"UTC"}, p.Functions[0].Args)
+
+	p, err = NewDatePartPredicate("c.created_at", DatePartHour, OperatorEqual, 9, time.Local)
+	assert.Equal(t, errDatePartLocation, err)
+	assert.Nil(t, p)
+}
@@ -15,3 +15,11 @@ type ScanError struct {
 func (e *ScanError) Error() string {
 	return e.err.Error()
 }
+
+type ParseError struct {
+	err error
+}
+
+func (e *ParseError) Error() string {
+	return e.err.Error()
+}
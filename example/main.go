@@ -113,6 +113,14 @@ func main() {
 	}
 	fmt.Println(c, tc, pc)
 
+	companies, err = searchByPhone(db, "03.04.05.06.07")
+	if err != nil {
+		log.Fatalf("failed to search by phone: %v", err)
+	}
+	for _, c := range companies {
+		fmt.Println(c, c.Phones)
+	}
+
 	id, err := insert(db)
 	if err != nil {
 		log.Fatalf("failed to insert: %v", err)
@@ -135,9 +143,9 @@ func main() {
 }
 
 func build(db *sqlx.DB, id interface{}) (string, []interface{}, error) {
-	return visisql.NewSelectService(db).Build(schema.fields, schema.tableName, nil, [][]*visisql.Predicate{{
+	return visisql.NewSelectService(db).Build(visisql.NewSelectQuery(schema.fields, schema.tableName).WithPredicates([]*visisql.Predicate{
 		visisql.NewPredicate("id", visisql.OperatorEqual, []interface{}{id}),
-	}}, nil, nil, nil)
+	}))
 }
 
 func query(db *sqlx.DB) ([]*Company, error) {
@@ -196,9 +204,9 @@ func get(db *sqlx.DB, id interface{}) (*Company, error) {
 	}
 
 	var company Company
-	if err := visisql.NewSelectService(db).Get(schema.fields, schema.tableName, nil, [][]*visisql.Predicate{{
+	if err := visisql.NewSelectService(db).Get(visisql.NewSelectQuery(schema.fields, schema.tableName).WithPredicates([]*visisql.Predicate{
 		visisql.NewPredicate("id", visisql.OperatorEqual, []interface{}{id}),
-	}}, nil, &company); err != nil {
+	}), &company); err != nil {
 		return nil, err
 	}
 
@@ -220,9 +228,9 @@ func search(db *sqlx.DB) ([]*Company, int64, int64, int64, error) {
 
 	var companies []*Company
 
-	c, tc, pc, err := visisql.NewSelectService(db).Search(schema.fields, schema.tableName, nil, nil, nil, []*visisql.OrderBy{
+	c, tc, pc, err := visisql.NewSelectService(db).Search(visisql.NewSelectQuery(schema.fields, schema.tableName).WithOrderBy(
 		visisql.NewOrderBy("id", visisql.OrderAsc),
-	}, nil, &companies)
+	), &companies)
 	if err != nil {
 		return nil, 0, 0, 0, err
 	}
@@ -230,6 +238,30 @@ func search(db *sqlx.DB) ([]*Company, int64, int64, int64, error) {
 	return companies, c, tc, pc, nil
 }
 
+func searchByPhone(db *sqlx.DB, phone string) ([]*Company, error) {
+	defer func() {
+		db.Exec(schema.drop)
+	}()
+
+	if _, err := db.Exec(schema.create); err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(schema.mocks); err != nil {
+		return nil, err
+	}
+
+	var companies []*Company
+
+	if _, _, _, err := visisql.NewSelectService(db).Search(visisql.NewSelectQuery(schema.fields, schema.tableName).WithPredicates([]*visisql.Predicate{
+		visisql.NewPredicate("phones", visisql.OperatorJSONContains, []interface{}{[]string{phone}}),
+	}), &companies); err != nil {
+		return nil, err
+	}
+
+	return companies, nil
+}
+
 func insert(db *sqlx.DB) (interface{}, error) {
 	defer func() {
 		db.Exec(schema.drop)
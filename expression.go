@@ -0,0 +1,203 @@
+package visisql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var errInvalidColumn = errors.New("column must be an identifier optionally qualified by a table alias")
+var errInvalidExpression = errors.New("expression must only contain columns, numbers, arithmetic operators, casts and allowed function calls")
+var errExpressionFunc = errors.New("expression function is not allowed")
+
+var columnRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+var allowedFuncs = map[string]bool{
+	"abs":        true,
+	"avg":        true,
+	"ceil":       true,
+	"coalesce":   true,
+	"count":      true,
+	"date_part":  true,
+	"date_trunc": true,
+	"floor":      true,
+	"greatest":   true,
+	"least":      true,
+	"length":     true,
+	"lower":      true,
+	"max":        true,
+	"min":        true,
+	"now":        true,
+	"nullif":     true,
+	"round":      true,
+	"sum":        true,
+	"timezone":   true,
+	"trim":       true,
+	"trunc":      true,
+	"unaccent":   true,
+	"upper":      true,
+}
+
+type Column string
+
+type Expression string
+
+func validateColumn(c Column) error {
+	if !columnRegexp.MatchString(string(c)) {
+		return fmt.Errorf("%w, got %q", errInvalidColumn, c)
+	}
+
+	return nil
+}
+
+func validateExpression(e Expression, cfg *buildConfig) error {
+	s := string(e)
+	invalid := func(i int) error {
+		return fmt.Errorf("%w, got %q at position %d", errInvalidExpression, e, i+1)
+	}
+
+	var parens []bool
+	expectOperand := true
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case isIdentStart(c):
+			if !expectOperand {
+				return invalid(i)
+			}
+
+			j := scanIdent(s, i)
+			if !columnRegexp.MatchString(s[i:j]) {
+				return invalid(i)
+			}
+
+			if k := skipSpaces(s, j); k < len(s) && s[k] == '(' {
+				if !cfg.allowsFunc(s[i:j]) {
+					return fmt.Errorf("%w, got %q", errExpressionFunc, s[i:j])
+				}
+
+				parens = append(parens, true)
+				i = k + 1
+				continue
+			}
+
+			expectOperand = false
+			i = j
+		case c >= '0' && c <= '9':
+			if !expectOperand {
+				return invalid(i)
+			}
+
+			for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+				i++
+			}
+			expectOperand = false
+		case c == '(':
+			if !expectOperand {
+				return invalid(i)
+			}
+
+			parens = append(parens, false)
+			i++
+		case c == ')':
+			if len(parens) == 0 || expectOperand && !(parens[len(parens)-1] && s[skipSpacesBack(s, i)] == '(') {
+				return invalid(i)
+			}
+
+			parens = parens[:len(parens)-1]
+			expectOperand = false
+			i++
+		case c == ',':
+			if len(parens) == 0 || !parens[len(parens)-1] || expectOperand {
+				return invalid(i)
+			}
+
+			expectOperand = true
+			i++
+		case c == ':':
+			if expectOperand || i+1 >= len(s) || s[i+1] != ':' {
+				return invalid(i)
+			}
+
+			k := skipSpaces(s, i+2)
+			if k >= len(s) || !isIdentStart(s[k]) {
+				return invalid(k)
+			}
+
+			j := scanIdent(s, k)
+			if strings.Contains(s[k:j], ".") {
+				return invalid(k)
+			}
+
+			i = j
+		case c == '*' && expectOperand && len(parens) > 0 && parens[len(parens)-1] && s[skipSpacesBack(s, i)] == '(':
+			// a star is only an operand as the sole argument of a call, e.g. count(*)
+			if k := skipSpaces(s, i+1); k >= len(s) || s[k] != ')' {
+				return invalid(i)
+			}
+
+			expectOperand = false
+			i++
+		case strings.IndexByte("+-*/%|", c) >= 0:
+			if c == '-' && i+1 < len(s) && s[i+1] == '-' || c == '/' && i+1 < len(s) && s[i+1] == '*' {
+				return invalid(i)
+			}
+
+			if expectOperand && c != '-' && c != '+' {
+				return invalid(i)
+			}
+
+			if c == '|' && i+1 < len(s) && s[i+1] == '|' {
+				i++
+			}
+
+			expectOperand = true
+			i++
+		default:
+			return invalid(i)
+		}
+	}
+
+	if expectOperand || len(parens) > 0 {
+		return invalid(len(s))
+	}
+
+	return nil
+}
+
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9'
+}
+
+func scanIdent(s string, i int) int {
+	for i < len(s) && (isIdentStart(s[i]) || s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+		i++
+	}
+
+	return i
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t' || s[i] == '\n') {
+		i++
+	}
+
+	return i
+}
+
+func skipSpacesBack(s string, i int) int {
+	i--
+	for i > 0 && (s[i] == ' ' || s[i] == '\t' || s[i] == '\n') {
+		i--
+	}
+
+	return i
+}
@@ -0,0 +1,88 @@
+package visisql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateExpression(t *testing.T) {
+	type in struct {
+		expr Expression
+	}
+
+	type out struct {
+		err error
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var tests = []*test{{
+		message: "qualified column",
+		in:      &in{expr: "u.created_at"},
+		out:     &out{err: nil},
+	}, {
+		message: "arithmetic with numbers and parens",
+		in:      &in{expr: "(b.total + 10.5) * -2"},
+		out:     &out{err: nil},
+	}, {
+		message: "allowed functions and cast",
+		in:      &in{expr: "coalesce(lower(a.name), upper(b.name)) || now()::text"},
+		out:     &out{err: nil},
+	}, {
+		message: "aggregates",
+		in:      &in{expr: "count(*) + count( * ) + sum(a.total) / max(a.id)"},
+		out:     &out{err: nil},
+	}, {
+		message: "star outside of a call",
+		in:      &in{expr: "(*)"},
+		out:     &out{err: errInvalidExpression},
+	}, {
+		message: "star with other arguments",
+		in:      &in{expr: "count(*, a.id)"},
+		out:     &out{err: errInvalidExpression},
+	}, {
+		message: "function not allowed",
+		in:      &in{expr: "pg_sleep(10)"},
+		out:     &out{err: errExpressionFunc},
+	}, {
+		message: "subquery",
+		in:      &in{expr: "(select password from users)"},
+		out:     &out{err: errInvalidExpression},
+	}, {
+		message: "string literal",
+		in:      &in{expr: "a.name || 'x'"},
+		out:     &out{err: errInvalidExpression},
+	}, {
+		message: "comment",
+		in:      &in{expr: "a.id -- comment"},
+		out:     &out{err: errInvalidExpression},
+	}, {
+		message: "statement separator",
+		in:      &in{expr: "a.id; drop table a"},
+		out:     &out{err: errInvalidExpression},
+	}, {
+		message: "unbalanced parens",
+		in:      &in{expr: "(a.id + 1"},
+		out:     &out{err: errInvalidExpression},
+	}, {
+		message: "empty",
+		in:      &in{expr: ""},
+		out:     &out{err: errInvalidExpression},
+	}}
+
+	for _, test := range tests {
+		err := validateExpression(test.in.expr, defaultBuildConfig)
+
+		if test.out.err != nil {
+			assert.True(t, errors.Is(err, test.out.err), test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+		}
+	}
+}
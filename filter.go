@@ -0,0 +1,550 @@
+package visisql
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errFilterUnexpected = errors.New("filter has an unexpected token")
+var errFilterUnterminated = errors.New("filter has an unterminated string")
+var errFilterField = errors.New("filter field is not allowed")
+var errFilterOperator = errors.New("filter operator is not allowed for this field")
+var errFilterValue = errors.New("filter value is invalid")
+var errFilterDepth = errors.New("filter has too many nested parentheses")
+var errFilterComplex = errors.New("filter has too many or combinations")
+var errFilterPrint = errors.New("predicate can not be printed as a filter")
+
+const filterMaxDepth = 32
+const filterMaxClauses = 64
+
+type filterTokenKind int
+
+const (
+	filterEOF filterTokenKind = iota
+	filterLParen
+	filterRParen
+	filterComma
+	filterOperator
+	filterString
+	filterWord
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+}
+
+var filterOperators = map[string]Operator{
+	"=":  OperatorEqual,
+	"!=": OperatorNotEqual,
+	"<>": OperatorNotEqual,
+	"<":  OperatorLessThan,
+	"<=": OperatorLessThanOrEqual,
+	">":  OperatorGreaterThan,
+	">=": OperatorGreaterThanOrEqual,
+	"~":  OperatorLike,
+}
+
+func isFilterWordChar(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9') || c == '.' || c == ':' || c == '+' || c == '-'
+}
+
+func lexFilter(s string) ([]*filterToken, error) {
+	var tokens []*filterToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, &filterToken{filterLParen, "(", i + 1})
+			i++
+		case c == ')':
+			tokens = append(tokens, &filterToken{filterRParen, ")", i + 1})
+			i++
+		case c == ',':
+			tokens = append(tokens, &filterToken{filterComma, ",", i + 1})
+			i++
+		case c == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+				}
+				b.WriteByte(s[j])
+			}
+
+			if j >= len(s) {
+				return nil, fmt.Errorf("%w at position %d", errFilterUnterminated, i+1)
+			}
+
+			tokens = append(tokens, &filterToken{filterString, b.String(), i + 1})
+			i = j + 1
+		case c == '<' || c == '>' || c == '!' || c == '=' || c == '~':
+			j := i + 1
+			if j < len(s) && (s[j] == '=' || (c == '<' && s[j] == '>')) {
+				j++
+			}
+
+			if _, ok := filterOperators[s[i:j]]; !ok {
+				return nil, fmt.Errorf("%w, got %q at position %d", errFilterUnexpected, s[i:j], i+1)
+			}
+
+			tokens = append(tokens, &filterToken{filterOperator, s[i:j], i + 1})
+			i = j
+		case isFilterWordChar(c):
+			j := i
+			for j < len(s) && isFilterWordChar(s[j]) {
+				j++
+			}
+
+			tokens = append(tokens, &filterToken{filterWord, s[i:j], i + 1})
+			i = j
+		default:
+			return nil, fmt.Errorf("%w, got %q at position %d", errFilterUnexpected, string(c), i+1)
+		}
+	}
+
+	return append(tokens, &filterToken{filterEOF, "", len(s) + 1}), nil
+}
+
+type filterNode struct {
+	or        bool
+	children  []*filterNode
+	predicate *Predicate
+}
+
+type filterParser struct {
+	fields map[string]*QueryStringField
+	tokens []*filterToken
+	pos    int
+	depth  int
+}
+
+func (fp *filterParser) peek() *filterToken {
+	return fp.tokens[fp.pos]
+}
+
+func (fp *filterParser) next() *filterToken {
+	t := fp.tokens[fp.pos]
+	if t.kind != filterEOF {
+		fp.pos++
+	}
+
+	return t
+}
+
+func (fp *filterParser) keyword(word string) bool {
+	t := fp.peek()
+	if t.kind == filterWord && strings.EqualFold(t.text, word) {
+		fp.pos++
+		return true
+	}
+
+	return false
+}
+
+func (fp *filterParser) expect(kind filterTokenKind) (*filterToken, error) {
+	t := fp.next()
+	if t.kind != kind {
+		return nil, unexpectedFilterToken(t)
+	}
+
+	return t, nil
+}
+
+func (fp *filterParser) expectKeyword(word string) error {
+	if !fp.keyword(word) {
+		return unexpectedFilterToken(fp.peek())
+	}
+
+	return nil
+}
+
+func unexpectedFilterToken(t *filterToken) error {
+	return fmt.Errorf("%w, got %q at position %d", errFilterUnexpected, t.text, t.pos)
+}
+
+func (fp *filterParser) parseOr() (*filterNode, error) {
+	left, err := fp.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	node := &filterNode{or: true, children: []*filterNode{left}}
+	for fp.keyword("or") {
+		right, err := fp.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		node.children = append(node.children, right)
+	}
+
+	return node, nil
+}
+
+func (fp *filterParser) parseAnd() (*filterNode, error) {
+	left, err := fp.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	node := &filterNode{children: []*filterNode{left}}
+	for fp.keyword("and") {
+		right, err := fp.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		node.children = append(node.children, right)
+	}
+
+	return node, nil
+}
+
+func (fp *filterParser) parseUnary() (*filterNode, error) {
+	t := fp.peek()
+	if t.kind != filterLParen {
+		p, err := fp.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+
+		return &filterNode{predicate: p}, nil
+	}
+
+	if fp.depth++; fp.depth > filterMaxDepth {
+		return nil, fmt.Errorf("%w at position %d", errFilterDepth, t.pos)
+	}
+
+	fp.next()
+	node, err := fp.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := fp.expect(filterRParen); err != nil {
+		return nil, err
+	}
+	fp.depth--
+
+	return node, nil
+}
+
+func (fp *filterParser) parseComparison() (*Predicate, error) {
+	t, err := fp.expect(filterWord)
+	if err != nil {
+		return nil, err
+	}
+
+	f, ok := fp.fields[t.text]
+	if !ok {
+		return nil, fmt.Errorf("%w, got %q at position %d", errFilterField, t.text, t.pos)
+	}
+
+	op := fp.peek()
+	var p *Predicate
+	switch {
+	case op.kind == filterOperator:
+		fp.next()
+		v, err := fp.parseValue(f)
+		if err != nil {
+			return nil, err
+		}
+
+		p = NewPredicate(f.column(), filterOperators[op.text], []interface{}{v})
+		if op.text == "~" {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w, got %q at position %d", errFilterValue, op.text, op.pos)
+			}
+
+			p.Values = []interface{}{"%" + escapeLike(s) + "%"}
+			p.Matching = MatchingCaseInsensitive
+		}
+	case fp.keyword("like"):
+		v, err := fp.parseValue(f)
+		if err != nil {
+			return nil, err
+		}
+
+		p = NewPredicate(f.column(), OperatorLike, []interface{}{v})
+	case fp.keyword("in"):
+		if _, err := fp.expect(filterLParen); err != nil {
+			return nil, err
+		}
+
+		p = NewPredicate(f.column(), OperatorIn, nil)
+		for {
+			v, err := fp.parseValue(f)
+			if err != nil {
+				return nil, err
+			}
+
+			p.Values = append(p.Values, v)
+			if fp.peek().kind != filterComma {
+				break
+			}
+			fp.next()
+		}
+
+		if _, err := fp.expect(filterRParen); err != nil {
+			return nil, err
+		}
+	case fp.keyword("between"):
+		from, err := fp.parseValue(f)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := fp.expectKeyword("and"); err != nil {
+			return nil, err
+		}
+
+		to, err := fp.parseValue(f)
+		if err != nil {
+			return nil, err
+		}
+
+		p = NewPredicate(f.column(), OperatorBetween, []interface{}{from, to})
+	case fp.keyword("is"):
+		if err := fp.expectKeyword("null"); err != nil {
+			return nil, err
+		}
+
+		p = NewPredicate(f.column(), OperatorIsNull, nil)
+	default:
+		return nil, unexpectedFilterToken(op)
+	}
+
+	if !f.allows(p.Operator) {
+		return nil, fmt.Errorf("%w, got %q at position %d", errFilterOperator, op.text, op.pos)
+	}
+
+	return p, nil
+}
+
+func (fp *filterParser) parseValue(f *QueryStringField) (interface{}, error) {
+	t := fp.next()
+	if t.kind != filterString && t.kind != filterWord {
+		return nil, unexpectedFilterToken(t)
+	}
+
+	if f.Parse == nil {
+		return t.text, nil
+	}
+
+	v, err := f.Parse(t.text)
+	if err != nil {
+		return nil, fmt.Errorf("%w, got %q at position %d: %s", errFilterValue, t.text, t.pos, err)
+	}
+
+	return v, nil
+}
+
+func (n *filterNode) cnf() ([][]*Predicate, error) {
+	if n.predicate != nil {
+		return [][]*Predicate{{n.predicate}}, nil
+	}
+
+	var res [][]*Predicate
+	for i, c := range n.children {
+		clauses, err := c.cnf()
+		if err != nil {
+			return nil, err
+		}
+
+		if !n.or {
+			res = append(res, clauses...)
+		} else if i == 0 {
+			res = clauses
+		} else {
+			var product [][]*Predicate
+			for _, a := range res {
+				for _, b := range clauses {
+					product = append(product, append(append([]*Predicate(nil), a...), b...))
+				}
+			}
+			if len(product) > filterMaxClauses {
+				return nil, errFilterComplex
+			}
+
+			res = product
+		}
+	}
+
+	return res, nil
+}
+
+type FilterParser struct {
+	fields  map[string]*QueryStringField
+	columns map[string]*QueryStringField
+}
+
+func NewFilterParser(fields ...*QueryStringField) *FilterParser {
+	fp := &FilterParser{fields: make(map[string]*QueryStringField, len(fields)), columns: make(map[string]*QueryStringField, len(fields))}
+	for _, f := range fields {
+		fp.fields[f.Name] = f
+		fp.columns[f.column()] = f
+	}
+
+	return fp
+}
+
+// Parse turns a filter like name ~ "acme" and (status in (active, trial) or created_at > 2024-01-01)
+// into predicates. Or combinations are distributed so that predicates are a conjunction of disjunctions.
+func (fp *FilterParser) Parse(filter string) ([][]*Predicate, error) {
+	tokens, err := lexFilter(filter)
+	if err != nil {
+		return nil, fmt.Errorf("visisql filter: %w", &ParseError{err})
+	}
+
+	p := &filterParser{fields: fp.fields, tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("visisql filter: %w", &ParseError{err})
+	}
+
+	if t := p.peek(); t.kind != filterEOF {
+		return nil, fmt.Errorf("visisql filter: %w", &ParseError{unexpectedFilterToken(t)})
+	}
+
+	predicates, err := node.cnf()
+	if err != nil {
+		return nil, fmt.Errorf("visisql filter: %w", &ParseError{err})
+	}
+
+	return predicates, nil
+}
+
+// Print renders predicates as a filter which can be parsed back.
+func (fp *FilterParser) Print(predicates [][]*Predicate) (string, error) {
+	ands := make([]string, 0, len(predicates))
+	for _, pAnd := range predicates {
+		ors := make([]string, 0, len(pAnd))
+		for _, pOr := range pAnd {
+			s, err := fp.print(pOr)
+			if err != nil {
+				return "", fmt.Errorf("visisql filter: %w", &QueryError{err})
+			}
+
+			ors = append(ors, s)
+		}
+
+		if len(ors) > 1 && len(predicates) > 1 {
+			ands = append(ands, fmt.Sprintf("(%s)", strings.Join(ors, " or ")))
+		} else {
+			ands = append(ands, strings.Join(ors, " or "))
+		}
+	}
+
+	return strings.Join(ands, " and "), nil
+}
+
+func (fp *FilterParser) print(p *Predicate) (string, error) {
+	f, ok := fp.columns[p.Field]
+	if !ok || len(p.Funcs) > 0 || len(p.Functions) > 0 || p.Query != nil || p.Array || p.Quantifier != "" {
+		return "", fmt.Errorf("%w, got %q", errFilterPrint, p.Field)
+	}
+
+	values := make([]string, 0, len(p.Values))
+	for _, v := range p.Values {
+		s, err := printFilterValue(v)
+		if err != nil {
+			return "", err
+		}
+
+		values = append(values, s)
+	}
+
+	if p.Matching != "" && !(p.IsOperator(OperatorLike) && p.Matching == MatchingCaseInsensitive) {
+		return "", fmt.Errorf("%w, got %q", errFilterPrint, p.Matching)
+	}
+
+	switch p.Operator {
+	case OperatorIsNull:
+		if len(values) == 0 {
+			return fmt.Sprintf("%s is null", f.Name), nil
+		}
+	case OperatorIn:
+		if len(values) > 0 {
+			return fmt.Sprintf("%s in (%s)", f.Name, strings.Join(values, ", ")), nil
+		}
+	case OperatorBetween:
+		if len(values) == 2 {
+			return fmt.Sprintf("%s between %s and %s", f.Name, values[0], values[1]), nil
+		}
+	case OperatorLike:
+		if len(values) == 1 && p.Matching == "" {
+			return fmt.Sprintf("%s like %s", f.Name, values[0]), nil
+		}
+
+		if s, ok := p.Values[0].(string); ok && len(values) == 1 {
+			if contains, ok := unescapeLikeContains(s); ok {
+				return fmt.Sprintf("%s ~ %s", f.Name, printFilterString(contains)), nil
+			}
+		}
+	default:
+		for symbol, o := range filterOperators {
+			if o == p.Operator && symbol != "<>" && symbol != "~" && len(values) == 1 {
+				return fmt.Sprintf("%s %s %s", f.Name, symbol, values[0]), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%w, got %q", errFilterPrint, p.Operator)
+}
+
+func printFilterValue(v interface{}) (string, error) {
+	switch e := v.(type) {
+	case string:
+		return printFilterString(e), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, bool:
+		return fmt.Sprint(e), nil
+	case float32:
+		return strconv.FormatFloat(float64(e), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(e, 'g', -1, 64), nil
+	case time.Time:
+		return printFilterString(e.Format(time.RFC3339Nano)), nil
+	}
+
+	return "", fmt.Errorf("%w, got %T", errFilterPrint, v)
+}
+
+func printFilterString(s string) string {
+	return fmt.Sprintf(`"%s"`, strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s))
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func unescapeLikeContains(s string) (string, bool) {
+	if len(s) < 2 || s[0] != '%' || s[len(s)-1] != '%' {
+		return "", false
+	}
+
+	var b strings.Builder
+	inner := s[1 : len(s)-1]
+	for i := 0; i < len(inner); i++ {
+		switch inner[i] {
+		case '\\':
+			if i+1 >= len(inner) {
+				return "", false
+			}
+			i++
+		case '%', '_':
+			return "", false
+		}
+		b.WriteByte(inner[i])
+	}
+
+	return b.String(), true
+}
@@ -0,0 +1,41 @@
+//go:build go1.18
+// +build go1.18
+
+package visisql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func FuzzFilterParse(f *testing.F) {
+	for _, s := range []string{
+		`name ~ "acme" and (status in (active, trial) or created_at > 2024-01-01)`,
+		`(id = 1 and name is null) or status = active`,
+		`id between 1 and 10 and name like "a\"b%"`,
+		`((((name != "x"))))`,
+		`name = "a`,
+	} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, filter string) {
+		predicates, err := filterTestParser.Parse(filter)
+		if err != nil {
+			return
+		}
+
+		printed, err := filterTestParser.Print(predicates)
+		if !assert.Nil(t, err, filter) {
+			return
+		}
+
+		reparsed, err := filterTestParser.Parse(printed)
+		if !assert.Nil(t, err, printed) {
+			return
+		}
+
+		assert.Equal(t, predicates, reparsed, printed)
+	})
+}
@@ -0,0 +1,183 @@
+package visisql
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/stretchr/testify/assert"
+)
+
+var filterTestParser = NewFilterParser(
+	NewQueryStringField("id", "c.id", OperatorEqual, OperatorIn, OperatorBetween, OperatorGreaterThan).WithParser(func(v string) (interface{}, error) {
+		return strconv.Atoi(v)
+	}),
+	NewQueryStringField("name", "c.name", OperatorEqual, OperatorNotEqual, OperatorLike, OperatorIsNull),
+	NewQueryStringField("status", "c.status", OperatorEqual, OperatorIn),
+	NewQueryStringField("created_at", "c.created_at", OperatorGreaterThan, OperatorLessThanOrEqual),
+)
+
+func TestFilterParse(t *testing.T) {
+	type in struct {
+		filter string
+	}
+
+	type out struct {
+		res []string
+		err error
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var tests = []*test{{
+		message: "conjunction of disjunctions",
+		in:      &in{filter: `name ~ "acme" and (status in (active, "trial") or created_at > 2024-01-01)`},
+		out: &out{res: []string{
+			"( c.name ILIKE $0 )",
+			"( c.status IN ($1, $2) OR c.created_at > $3 )",
+		}},
+	}, {
+		message: "or is distributed over and",
+		in:      &in{filter: `(id = 1 and name is null) OR status = active`},
+		out: &out{res: []string{
+			"( c.id = $0 OR c.status = $1 )",
+			"( c.name IS NULL OR c.status = $2 )",
+		}},
+	}, {
+		message: "between, like and escaped string",
+		in:      &in{filter: `id between 1 and 10 and name like "a\"b%"`},
+		out: &out{res: []string{
+			"( c.id BETWEEN $0 AND $1 )",
+			"( c.name LIKE $2 )",
+		}},
+	}, {
+		message: "unknown field",
+		in:      &in{filter: `name = a and password = b`},
+		out:     &out{err: &ParseError{fmt.Errorf("%w, got %q at position %d", errFilterField, "password", 14)}},
+	}, {
+		message: "operator not allowed",
+		in:      &in{filter: `status ~ a`},
+		out:     &out{err: &ParseError{fmt.Errorf("%w, got %q at position %d", errFilterOperator, "~", 8)}},
+	}, {
+		message: "missing closing parenthesis",
+		in:      &in{filter: `(name = a or id = 1`},
+		out:     &out{err: &ParseError{fmt.Errorf("%w, got %q at position %d", errFilterUnexpected, "", 20)}},
+	}, {
+		message: "unterminated string",
+		in:      &in{filter: `name = "a`},
+		out:     &out{err: &ParseError{fmt.Errorf("%w at position %d", errFilterUnterminated, 8)}},
+	}, {
+		message: "unexpected character",
+		in:      &in{filter: `name = a; drop table c`},
+		out:     &out{err: &ParseError{fmt.Errorf("%w, got %q at position %d", errFilterUnexpected, ";", 9)}},
+	}, {
+		message: "invalid value",
+		in:      &in{filter: `id = abc`},
+		out:     &out{err: &ParseError{fmt.Errorf("%w, got %q at position %d: %s", errFilterValue, "abc", 6, `strconv.Atoi: parsing "abc": invalid syntax`)}},
+	}}
+
+	for _, test := range tests {
+		predicates, err := filterTestParser.Parse(test.in.filter)
+
+		if test.out.err != nil {
+			var pe *ParseError
+
+			assert.True(t, errors.As(err, &pe), test.message)
+			assert.Equal(t, test.out.err.Error(), pe.Error(), test.message)
+			assert.Nil(t, predicates, test.message)
+			continue
+		}
+
+		assert.Nil(t, err, test.message)
+
+		res, err := predicatesToStrings(predicates, &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond, defaultBuildConfig)
+		assert.Nil(t, err, test.message)
+		assert.Equal(t, test.out.res, res, test.message)
+	}
+}
+
+func TestFilterParseClauses(t *testing.T) {
+	and := strings.TrimSuffix(strings.Repeat("id = 1 and ", filterMaxClauses+1), " and ")
+
+	predicates, err := filterTestParser.Parse(and)
+	assert.Nil(t, err)
+	assert.Len(t, predicates, filterMaxClauses+1)
+
+	group := "(" + strings.TrimSuffix(strings.Repeat("id = 1 and ", 9), " and ") + ")"
+
+	predicates, err = filterTestParser.Parse(group + " or " + group)
+
+	var pe *ParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, &ParseError{errFilterComplex}, pe)
+	assert.Nil(t, predicates)
+}
+
+func TestFilterPrint(t *testing.T) {
+	type in struct {
+		predicates [][]*Predicate
+	}
+
+	type out struct {
+		res string
+		err error
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var tests = []*test{{
+		message: "conjunction of disjunctions",
+		in: &in{predicates: [][]*Predicate{{
+			NewPredicate("c.name", OperatorLike, []interface{}{`%a\_b%`}).WithMatching(MatchingCaseInsensitive),
+		}, {
+			NewPredicate("c.status", OperatorIn, []interface{}{"active", "trial"}),
+			NewPredicate("c.id", OperatorBetween, []interface{}{1, 10}),
+		}}},
+		out: &out{res: `name ~ "a_b" and (status in ("active", "trial") or id between 1 and 10)`},
+	}, {
+		message: "single disjunction",
+		in: &in{predicates: [][]*Predicate{{
+			NewPredicate("c.name", OperatorNotEqual, []interface{}{`a"b`}),
+			NewPredicate("c.name", OperatorIsNull, nil),
+		}}},
+		out: &out{res: `name != "a\"b" or name is null`},
+	}, {
+		message: "unknown column",
+		in: &in{predicates: [][]*Predicate{{
+			NewPredicate("c.password", OperatorEqual, []interface{}{"a"}),
+		}}},
+		out: &out{err: &QueryError{fmt.Errorf("%w, got %q", errFilterPrint, "c.password")}},
+	}, {
+		message: "operator without filter syntax",
+		in: &in{predicates: [][]*Predicate{{
+			NewPredicate("c.name", OperatorJSONHasKey, []interface{}{"a"}),
+		}}},
+		out: &out{err: &QueryError{fmt.Errorf("%w, got %q", errFilterPrint, OperatorJSONHasKey)}},
+	}}
+
+	for _, test := range tests {
+		res, err := filterTestParser.Print(test.in.predicates)
+
+		if test.out.err != nil {
+			var qe *QueryError
+
+			assert.True(t, errors.As(err, &qe), test.message)
+			assert.Equal(t, test.out.err.Error(), qe.Error(), test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+		}
+
+		assert.Equal(t, test.out.res, res, test.message)
+	}
+}
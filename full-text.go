@@ -0,0 +1,69 @@
+package visisql
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/huandu/go-sqlbuilder"
+)
+
+var errInvalidTextSearchConfig = errors.New("text search config must be an identifier optionally qualified by a schema")
+var errOperatorFullText = errors.New("predicate must have only one string value when operator is full text or tsvector match")
+var errHeadline = errors.New("headline must have a field, a query and an alias")
+
+type Headline struct {
+	Field  string `json:"field"`
+	Config string `json:"config,omitempty"`
+	Query  string `json:"query"`
+	Alias  string `json:"alias"`
+}
+
+func NewHeadline(field string, config string, query string, alias string) *Headline {
+	return &Headline{Field: field, Config: config, Query: query, Alias: alias}
+}
+
+func (h *Headline) toString(cond *sqlbuilder.Cond) (string, error) {
+	if h.Field == "" || h.Query == "" {
+		return "", errHeadline
+	}
+
+	field, err := fieldColumn(h.Field)
+	if err != nil {
+		return "", err
+	}
+
+	if err := validateIdentifier(h.Alias); err != nil {
+		return "", err
+	}
+
+	config, err := textSearchConfig(h.Config)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("ts_headline(%s%s, %s) AS %s", config, field, tsQuery(config, cond.Args.Add(h.Query)), h.Alias), nil
+}
+
+func textSearchConfig(config string) (string, error) {
+	if config == "" {
+		return "", nil
+	}
+
+	if !columnRegexp.MatchString(config) {
+		return "", fmt.Errorf("%w, got %q", errInvalidTextSearchConfig, config)
+	}
+
+	return fmt.Sprintf("'%s', ", config), nil
+}
+
+func tsVector(config string, field string, stored bool) string {
+	if stored {
+		return field
+	}
+
+	return fmt.Sprintf("to_tsvector(%s%s)", config, field)
+}
+
+func tsQuery(config string, placeholder string) string {
+	return fmt.Sprintf("websearch_to_tsquery(%s%s)", config, placeholder)
+}
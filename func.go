@@ -0,0 +1,83 @@
+package visisql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/huandu/go-sqlbuilder"
+)
+
+var errFuncOperandPosition = errors.New("func operand position must be between 0 and the number of args")
+var errFuncSide = errors.New("func side must be BOTH, FIELD or VALUE")
+
+type FuncSide string
+
+const (
+	FuncSideBoth  FuncSide = "BOTH"
+	FuncSideField FuncSide = "FIELD"
+	FuncSideValue FuncSide = "VALUE"
+)
+
+type Func struct {
+	Name            string        `json:"name"`
+	Side            FuncSide      `json:"side,omitempty"`
+	Args            []interface{} `json:"args,omitempty"`
+	OperandPosition int           `json:"operandPosition,omitempty"`
+}
+
+func NewFunc(name string, side FuncSide, args ...interface{}) *Func {
+	return &Func{Name: name, Side: side, Args: args}
+}
+
+func (f *Func) WithOperandAt(position int) *Func {
+	f.OperandPosition = position
+	return f
+}
+
+func (f *Func) appliesTo(side FuncSide) bool {
+	return f.Side == "" || f.Side == FuncSideBoth || f.Side == side
+}
+
+func (f *Func) validate(cfg *buildConfig) error {
+	if err := validateFuncName(f.Name, cfg); err != nil {
+		return err
+	}
+
+	switch f.Side {
+	case "", FuncSideBoth, FuncSideField, FuncSideValue:
+	default:
+		return fmt.Errorf("%w, got %q", errFuncSide, f.Side)
+	}
+
+	if f.OperandPosition < 0 || f.OperandPosition > len(f.Args) {
+		return fmt.Errorf("%w, got %d", errFuncOperandPosition, f.OperandPosition)
+	}
+
+	for _, a := range f.Args {
+		if err := validateReference(a, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *Func) wrap(p *Predicate, cond *sqlbuilder.Cond, val string) string {
+	args := make([]string, 0, len(f.Args)+1)
+	for _, a := range f.Args {
+		args = append(args, p.arg(cond, a))
+	}
+
+	args = append(args[:f.OperandPosition], append([]string{val}, args[f.OperandPosition:]...)...)
+
+	return fmt.Sprintf("%s(%s)", f.Name, strings.Join(args, ", "))
+}
+
+func validateFuncName(name string, cfg *buildConfig) error {
+	if !cfg.allowsFunc(name) {
+		return fmt.Errorf("%w, got %q", errExpressionFunc, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package visisql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var errPolygonRing = errors.New("polygon rings must have at least three distinct points")
+var errOperatorGeo = errors.New("predicate must have only one geometry value when operator is intersects or contains")
+var errOperatorGeoDWithin = errors.New("predicate must have one geometry value and one distance when operator is dwithin")
+
+const DefaultSRID = 4326
+
+type Geometry interface {
+	driver.Valuer
+	WKT() string
+}
+
+type Point struct {
+	Longitude float64 `json:"longitude"`
+	Latitude  float64 `json:"latitude"`
+	SRID      int     `json:"srid,omitempty"`
+}
+
+func NewPoint(longitude, latitude float64) *Point {
+	return &Point{Longitude: longitude, Latitude: latitude, SRID: DefaultSRID}
+}
+
+func (p *Point) WKT() string {
+	return fmt.Sprintf("POINT(%s)", p.coordinates())
+}
+
+func (p *Point) Value() (driver.Value, error) {
+	if p == nil {
+		return nil, errOperatorGeo
+	}
+
+	return ewkt(p.SRID, p.WKT()), nil
+}
+
+func (p *Point) coordinates() string {
+	return fmt.Sprintf("%s %s", strconv.FormatFloat(p.Longitude, 'f', -1, 64), strconv.FormatFloat(p.Latitude, 'f', -1, 64))
+}
+
+type Polygon struct {
+	Rings [][]*Point `json:"rings"`
+	SRID  int        `json:"srid,omitempty"`
+}
+
+func NewPolygon(exterior ...*Point) *Polygon {
+	return &Polygon{Rings: [][]*Point{exterior}, SRID: DefaultSRID}
+}
+
+func (p *Polygon) WKT() string {
+	rings := make([]string, 0, len(p.Rings))
+	for _, r := range p.Rings {
+		coords := make([]string, 0, len(r)+1)
+		for _, pt := range r {
+			coords = append(coords, pt.coordinates())
+		}
+
+		if len(r) > 0 && (r[0].Longitude != r[len(r)-1].Longitude || r[0].Latitude != r[len(r)-1].Latitude) {
+			coords = append(coords, r[0].coordinates())
+		}
+
+		rings = append(rings, fmt.Sprintf("(%s)", strings.Join(coords, ", ")))
+	}
+
+	return fmt.Sprintf("POLYGON(%s)", strings.Join(rings, ", "))
+}
+
+func (p *Polygon) Value() (driver.Value, error) {
+	if p == nil {
+		return nil, errOperatorGeo
+	}
+
+	for _, r := range p.Rings {
+		positions := make(map[[2]float64]bool, len(r))
+		for _, pt := range r {
+			if pt == nil {
+				return nil, errOperatorGeo
+			}
+
+			positions[[2]float64{pt.Longitude, pt.Latitude}] = true
+		}
+
+		// a closed ring like (a, b, a) has only two distinct positions
+		if len(positions) < 3 {
+			return nil, errPolygonRing
+		}
+	}
+
+	return ewkt(p.SRID, p.WKT()), nil
+}
+
+func ewkt(srid int, wkt string) string {
+	if srid == 0 {
+		return wkt
+	}
+
+	return fmt.Sprintf("SRID=%d;%s", srid, wkt)
+}
+
+func geometryValue(v interface{}) (Geometry, error) {
+	g, ok := v.(Geometry)
+	if !ok {
+		return nil, errOperatorGeo
+	}
+
+	if _, err := g.Value(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
@@ -0,0 +1,65 @@
+package visisql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeometryValue(t *testing.T) {
+	type in struct {
+		geometry Geometry
+	}
+
+	type out struct {
+		res interface{}
+		err error
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var tests = []*test{{
+		message: "point",
+		in:      &in{geometry: NewPoint(2.3522, 48.8566)},
+		out:     &out{res: "SRID=4326;POINT(2.3522 48.8566)"},
+	}, {
+		message: "point without srid",
+		in:      &in{geometry: &Point{Longitude: -1.5, Latitude: 47}},
+		out:     &out{res: "POINT(-1.5 47)"},
+	}, {
+		message: "polygon is closed",
+		in:      &in{geometry: NewPolygon(NewPoint(0, 0), NewPoint(0, 1), NewPoint(1, 1))},
+		out:     &out{res: "SRID=4326;POLYGON((0 0, 0 1, 1 1, 0 0))"},
+	}, {
+		message: "polygon with invalid ring",
+		in:      &in{geometry: NewPolygon(NewPoint(0, 0), NewPoint(0, 1))},
+		out:     &out{err: errPolygonRing},
+	}, {
+		message: "polygon with closed ring of two positions",
+		in:      &in{geometry: NewPolygon(NewPoint(0, 0), NewPoint(0, 1), NewPoint(0, 0))},
+		out:     &out{err: errPolygonRing},
+	}, {
+		message: "polygon with nil point",
+		in:      &in{geometry: NewPolygon(NewPoint(0, 0), nil, NewPoint(1, 1))},
+		out:     &out{err: errOperatorGeo},
+	}, {
+		message: "nil point",
+		in:      &in{geometry: (*Point)(nil)},
+		out:     &out{err: errOperatorGeo},
+	}, {
+		message: "nil polygon",
+		in:      &in{geometry: (*Polygon)(nil)},
+		out:     &out{err: errOperatorGeo},
+	}}
+
+	for _, test := range tests {
+		res, err := test.in.geometry.Value()
+
+		assert.Equal(t, test.out.err, err, test.message)
+		assert.Equal(t, test.out.res, res, test.message)
+	}
+}
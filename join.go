@@ -1,19 +1,170 @@
 package visisql
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/huandu/go-sqlbuilder"
+)
+
+var errJoinTableAndSubQuery = errors.New("join must have either a table or a subquery")
+var errJoinLateralWithoutSubQuery = errors.New("lateral join must have a subquery")
+var errJoinCrossCondition = errors.New("cross join must not have a condition")
+var errJoinUsingCondition = errors.New("join must not have both using columns and a condition")
+var errJoinCondition = errors.New("join must have a condition unless it is a cross join")
+
 type JoinOption string
 
 const (
 	LeftJoin  JoinOption = "LEFT"
 	InnerJoin JoinOption = "INNER"
 	RightJoin JoinOption = "RIGHT"
+	FullJoin  JoinOption = "FULL OUTER"
+	CrossJoin JoinOption = "CROSS"
 )
 
 type Join struct {
-	option JoinOption
-	table  string
-	on     string
+	Option     JoinOption     `json:"option"`
+	Table      string         `json:"table,omitempty"`
+	Query      *SelectQuery   `json:"query,omitempty"`
+	Alias      string         `json:"alias,omitempty"`
+	Lateral    bool           `json:"lateral,omitempty"`
+	On         string         `json:"on,omitempty"`
+	Predicates [][]*Predicate `json:"predicates,omitempty"`
+	Using      []string       `json:"using,omitempty"`
 }
 
 func NewJoin(option JoinOption, table string, on string) *Join {
-	return &Join{option: option, table: table, on: on}
+	return &Join{Option: option, Table: table, On: on}
+}
+
+func NewPredicateJoin(option JoinOption, table string, predicates [][]*Predicate) *Join {
+	return &Join{Option: option, Table: table, Predicates: predicates}
+}
+
+func NewUsingJoin(option JoinOption, table string, using []string) *Join {
+	return &Join{Option: option, Table: table, Using: using}
+}
+
+func NewCrossJoin(table string) *Join {
+	return &Join{Option: CrossJoin, Table: table}
+}
+
+func NewSubQueryJoin(option JoinOption, query *SelectQuery, alias string, on string) *Join {
+	return &Join{Option: option, Query: query, Alias: alias, On: on}
+}
+
+func NewLateralJoin(option JoinOption, query *SelectQuery, alias string, on string) *Join {
+	return &Join{Option: option, Query: query, Alias: alias, Lateral: true, On: on}
+}
+
+func (j *Join) clone() *Join {
+	c := *j
+	if j.Query != nil {
+		c.Query = j.Query.Clone()
+	}
+
+	c.Predicates = clonePredicates(j.Predicates)
+	c.Using = append([]string(nil), j.Using...)
+
+	return &c
+}
+
+func (j *Join) table(args *sqlbuilder.Args, cfg *buildConfig) (string, error) {
+	if j.Query == nil {
+		if j.Lateral {
+			return "", errJoinLateralWithoutSubQuery
+		}
+
+		return j.Table, nil
+	}
+
+	if j.Table != "" {
+		return "", errJoinTableAndSubQuery
+	}
+
+	if err := validateIdentifier(j.Alias); err != nil {
+		return "", err
+	}
+
+	builder, err := newQueryBuilder(j.Query, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	table := fmt.Sprintf("(%s) AS %s", args.Add(builder), j.Alias)
+	if j.Lateral {
+		table = fmt.Sprintf("LATERAL %s", table)
+	}
+
+	return table, nil
+}
+
+func (j *Join) conditions(cond *sqlbuilder.Cond, cfg *buildConfig) ([]string, error) {
+	hasCondition := j.On != "" || len(j.Predicates) > 0
+
+	if j.Option == CrossJoin {
+		if hasCondition || len(j.Using) > 0 {
+			return nil, fmt.Errorf("visisql join: %w", &QueryError{errJoinCrossCondition})
+		}
+
+		return nil, nil
+	}
+
+	if len(j.Using) > 0 {
+		if hasCondition {
+			return nil, fmt.Errorf("visisql join: %w", &QueryError{errJoinUsingCondition})
+		}
+
+		for _, u := range j.Using {
+			if err := validateIdentifier(u); err != nil {
+				return nil, fmt.Errorf("visisql join: %w", &QueryError{err})
+			}
+		}
+
+		return nil, nil
+	}
+
+	if !hasCondition {
+		return nil, fmt.Errorf("visisql join: %w", &QueryError{errJoinCondition})
+	}
+
+	var on []string
+	if j.On != "" {
+		on = append(on, j.On)
+	}
+
+	sPs, err := predicatesToStrings(j.Predicates, cond, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(on, sPs...), nil
+}
+
+func addJoins(joins []*Join, builder *sqlbuilder.SelectBuilder, cfg *buildConfig) error {
+	for _, j := range joins {
+		table, err := j.table(builder.Args, cfg)
+		if err != nil {
+			return fmt.Errorf("visisql join: %w", &QueryError{err})
+		}
+
+		on, err := j.conditions(&builder.Cond, cfg)
+		if err != nil {
+			return err
+		}
+
+		if len(j.Using) > 0 {
+			table = fmt.Sprintf("%s USING (%s)", table, strings.Join(j.Using, ", "))
+		}
+
+		if j.Option == InnerJoin {
+			builder.Join(table, on...)
+		} else {
+			builder.JoinWithOption(sqlbuilder.JoinOption(j.Option), table, on...)
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,78 @@
+package visisql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var errInvalidJSONPath = errors.New("json path segments must only contain letters, digits, underscores and dashes")
+
+var jsonPathSegmentRegexp = regexp.MustCompile(`^(?:'([A-Za-z0-9_-]+)'|([A-Za-z0-9_-]+))$`)
+var jsonPathIndexRegexp = regexp.MustCompile(`^-?[0-9]+$`)
+var jsonPathSplitRegexp = regexp.MustCompile(`->>?`)
+
+func JSONPath(column string, segments ...string) string {
+	if len(segments) == 0 {
+		return column
+	}
+
+	path := column
+	for i, s := range segments {
+		if i == len(segments)-1 {
+			path = fmt.Sprintf("%s->>%s", path, s)
+		} else {
+			path = fmt.Sprintf("%s->%s", path, s)
+		}
+	}
+
+	return path
+}
+
+func isJSONPath(field string) bool {
+	return strings.Contains(field, "->")
+}
+
+func parseJSONPath(field string) (string, error) {
+	column := jsonPathSplitRegexp.Split(field, 2)[0]
+	if err := validateColumn(Column(strings.TrimSpace(column))); err != nil {
+		return "", err
+	}
+
+	ops := jsonPathSplitRegexp.FindAllString(field, -1)
+	segments := jsonPathSplitRegexp.Split(field, -1)[1:]
+
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(column))
+	for i, s := range segments {
+		s = strings.TrimSpace(s)
+
+		m := jsonPathSegmentRegexp.FindStringSubmatch(s)
+		if m == nil {
+			return "", fmt.Errorf("%w, got %q", errInvalidJSONPath, s)
+		}
+
+		b.WriteString(ops[i])
+		if jsonPathIndexRegexp.MatchString(s) {
+			b.WriteString(s)
+		} else {
+			b.WriteString(fmt.Sprintf("'%s%s'", m[1], m[2]))
+		}
+	}
+
+	return b.String(), nil
+}
+
+// fieldColumn validates a column or a json path field and returns it as written in queries.
+func fieldColumn(field string) (string, error) {
+	if isJSONPath(field) {
+		return parseJSONPath(field)
+	}
+
+	if err := validateColumn(Column(field)); err != nil {
+		return "", err
+	}
+
+	return field, nil
+}
@@ -0,0 +1,96 @@
+package visisql
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errMatching = errors.New("predicate matching must be CASE INSENSITIVE, ACCENT INSENSITIVE or INSENSITIVE and operator must be equal, not equal, like or in with values, without quantifier")
+
+type Matching string
+
+const (
+	MatchingCaseInsensitive   Matching = "CASE INSENSITIVE"
+	MatchingAccentInsensitive Matching = "ACCENT INSENSITIVE"
+	MatchingInsensitive       Matching = "INSENSITIVE"
+)
+
+func (m Matching) caseInsensitive() bool {
+	return m == MatchingCaseInsensitive || m == MatchingInsensitive
+}
+
+func (m Matching) accentInsensitive() bool {
+	return m == MatchingAccentInsensitive || m == MatchingInsensitive
+}
+
+func (p *Predicate) WithMatching(matching Matching) *Predicate {
+	p.Matching = matching
+	return p
+}
+
+func (p *Predicate) matchable() bool {
+	if p.Quantifier != "" {
+		return false
+	}
+
+	switch p.Operator {
+	case OperatorEqual, OperatorNotEqual, OperatorLike:
+		return true
+	case OperatorIn:
+		return !p.Array && p.Query == nil
+	}
+
+	return false
+}
+
+func (p *Predicate) matching(cfg *buildConfig) Matching {
+	if p.Matching != "" {
+		return p.Matching
+	}
+
+	if m, ok := cfg.matchings[p.Field]; ok && p.matchable() {
+		return m
+	}
+
+	return ""
+}
+
+func (p *Predicate) validateMatching(cfg *buildConfig) error {
+	m := p.matching(cfg)
+	if m == "" {
+		return nil
+	}
+
+	switch m {
+	case MatchingCaseInsensitive, MatchingAccentInsensitive, MatchingInsensitive:
+	default:
+		return fmt.Errorf("%w, got %q", errMatching, m)
+	}
+
+	if !p.matchable() {
+		return fmt.Errorf("%w, got %q", errMatching, p.Operator)
+	}
+
+	return nil
+}
+
+func (p *Predicate) wrapMatching(cfg *buildConfig, val string) string {
+	m := p.matching(cfg)
+	if m.accentInsensitive() {
+		val = fmt.Sprintf("%s(%s)", cfg.unaccentFunc, val)
+	}
+
+	if m.caseInsensitive() && !p.IsOperator(OperatorLike) {
+		val = fmt.Sprintf("lower(%s)", val)
+	}
+
+	return val
+}
+
+func (p *Predicate) likeOperator(cfg *buildConfig) string {
+	if p.matching(cfg).caseInsensitive() {
+		return "ILIKE"
+	}
+
+	return "LIKE"
+}
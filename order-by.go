@@ -1,6 +1,24 @@
 package visisql
 
-import "fmt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/lib/pq"
+)
+
+var errOrderByRank = errors.New("order by rank must have only one string value")
+var errOrderByDistance = errors.New("order by distance must have only one value")
+var errUnknownOrder = errors.New("order must be ASC or DESC")
+var errUnknownNulls = errors.New("order nulls must be FIRST or LAST")
+var errOrderByValues = errors.New("order by position or priority must have at least one value")
+var errInvalidCollation = errors.New("collation must only contain letters, digits, underscores, dashes and dots")
+
+var collationRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
 
 type Order string
 
@@ -9,15 +27,249 @@ const (
 	OrderDesc Order = "DESC"
 )
 
+// UnmarshalText accepts ASC and DESC case insensitively, empty for the default order.
+func (o *Order) UnmarshalText(text []byte) error {
+	switch order := Order(strings.ToUpper(strings.TrimSpace(string(text)))); order {
+	case "", OrderAsc, OrderDesc:
+		*o = order
+		return nil
+	}
+
+	return fmt.Errorf("%w, got %q", errUnknownOrder, string(text))
+}
+
+func (o *Order) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("%w, got %s", errUnknownOrder, b)
+	}
+
+	return o.UnmarshalText([]byte(s))
+}
+
+func (o Order) validate() error {
+	switch o {
+	case "", OrderAsc, OrderDesc:
+		return nil
+	}
+
+	return fmt.Errorf("%w, got %q", errUnknownOrder, string(o))
+}
+
+type OrderByKind string
+
+const (
+	OrderByFullTextRank OrderByKind = "FULL TEXT RANK"
+	OrderByTSVectorRank OrderByKind = "TSVECTOR RANK"
+	OrderByDistance     OrderByKind = "DISTANCE"
+	OrderByPosition     OrderByKind = "POSITION"
+	OrderByPriority     OrderByKind = "PRIORITY"
+	OrderByExpression   OrderByKind = "EXPRESSION"
+	OrderByRaw          OrderByKind = "RAW"
+)
+
+type Nulls string
+
+const (
+	NullsFirst Nulls = "FIRST"
+	NullsLast  Nulls = "LAST"
+)
+
+// UnmarshalText accepts FIRST and LAST case insensitively, empty for the default.
+func (n *Nulls) UnmarshalText(text []byte) error {
+	nulls := Nulls(strings.ToUpper(strings.TrimSpace(string(text))))
+	if err := nulls.validate(); err != nil {
+		return fmt.Errorf("%w, got %q", errUnknownNulls, string(text))
+	}
+
+	*n = nulls
+	return nil
+}
+
+func (n Nulls) validate() error {
+	switch n {
+	case "", NullsFirst, NullsLast:
+		return nil
+	}
+
+	return fmt.Errorf("%w, got %q", errUnknownNulls, string(n))
+}
+
 type OrderBy struct {
-	Field string `json:"field"`
-	Order Order  `json:"order"`
+	Field     string        `json:"field"`
+	Order     Order         `json:"order"`
+	Kind      OrderByKind   `json:"kind,omitempty"`
+	Values    []interface{} `json:"values,omitempty"`
+	Config    string        `json:"config,omitempty"`
+	Nulls     Nulls         `json:"nulls,omitempty"`
+	Collation string        `json:"collation,omitempty"`
+
+	Raw string `json:"-"`
 }
 
 func NewOrderBy(field string, order Order) *OrderBy {
 	return &OrderBy{Field: field, Order: order}
 }
 
-func (ob *OrderBy) toString() string {
-	return fmt.Sprintf("%s %s", ob.Field, ob.Order)
+func NewFullTextRankOrderBy(field string, config string, query string, order Order) *OrderBy {
+	return &OrderBy{Field: field, Order: order, Kind: OrderByFullTextRank, Values: []interface{}{query}, Config: config}
+}
+
+func NewTSVectorRankOrderBy(field string, config string, query string, order Order) *OrderBy {
+	return &OrderBy{Field: field, Order: order, Kind: OrderByTSVectorRank, Values: []interface{}{query}, Config: config}
+}
+
+func NewDistanceOrderBy(field string, value interface{}, order Order) *OrderBy {
+	return &OrderBy{Field: field, Order: order, Kind: OrderByDistance, Values: []interface{}{value}}
+}
+
+// NewPositionOrderBy orders by the position of field in values, e.g. to keep the order of given ids.
+func NewPositionOrderBy(field string, values []interface{}, order Order) *OrderBy {
+	return &OrderBy{Field: field, Order: order, Kind: OrderByPosition, Values: values}
+}
+
+// NewPriorityOrderBy orders field values as given first, then other values.
+func NewPriorityOrderBy(field string, values []interface{}, order Order) *OrderBy {
+	return &OrderBy{Field: field, Order: order, Kind: OrderByPriority, Values: values}
+}
+
+func NewExpressionOrderBy(expr Expression, order Order) *OrderBy {
+	return &OrderBy{Field: string(expr), Order: order, Kind: OrderByExpression}
+}
+
+// NewRawOrderBy orders by an expression written in the query as is, whose ? placeholders are bound to args.
+// The expression is NOT SAFE for client input.
+func NewRawOrderBy(expr string, order Order, args ...interface{}) *OrderBy {
+	return &OrderBy{Order: order, Kind: OrderByRaw, Values: args, Raw: expr}
+}
+
+func (ob *OrderBy) WithNulls(nulls Nulls) *OrderBy {
+	ob.Nulls = nulls
+	return ob
+}
+
+func (ob *OrderBy) WithCollation(collation string) *OrderBy {
+	ob.Collation = collation
+	return ob
+}
+
+func (ob *OrderBy) toString(cond *sqlbuilder.Cond, cfg *buildConfig) (string, error) {
+	if err := ob.Order.validate(); err != nil {
+		return "", err
+	}
+
+	if err := ob.Nulls.validate(); err != nil {
+		return "", err
+	}
+
+	expr, err := ob.expression(cond, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	parts := []string{expr}
+	if ob.Collation != "" {
+		if !collationRegexp.MatchString(ob.Collation) {
+			return "", fmt.Errorf("%w, got %q", errInvalidCollation, ob.Collation)
+		}
+
+		parts = append(parts, fmt.Sprintf(`COLLATE "%s"`, ob.Collation))
+	}
+
+	if ob.Order != "" {
+		parts = append(parts, string(ob.Order))
+	}
+
+	if ob.Nulls != "" {
+		parts = append(parts, fmt.Sprintf("NULLS %s", ob.Nulls))
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+func (ob *OrderBy) expression(cond *sqlbuilder.Cond, cfg *buildConfig) (string, error) {
+	switch ob.Kind {
+	case OrderByFullTextRank, OrderByTSVectorRank:
+		if len(ob.Values) != 1 {
+			return "", errOrderByRank
+		}
+
+		if _, ok := ob.Values[0].(string); !ok {
+			return "", errOrderByRank
+		}
+
+		field, err := fieldColumn(ob.Field)
+		if err != nil {
+			return "", err
+		}
+
+		config, err := textSearchConfig(ob.Config)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("ts_rank(%s, %s)", tsVector(config, field, ob.Kind == OrderByTSVectorRank), tsQuery(config, cond.Args.Add(ob.Values[0]))), nil
+	case OrderByDistance:
+		if len(ob.Values) != 1 {
+			return "", errOrderByDistance
+		}
+
+		field, err := fieldColumn(ob.Field)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%s <-> %s", field, cond.Args.Add(ob.Values[0])), nil
+	case OrderByPosition:
+		if len(ob.Values) == 0 {
+			return "", errOrderByValues
+		}
+
+		if err := validateColumn(Column(ob.Field)); err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("array_position(%s, %s)", cond.Args.Add(pq.Array(ob.Values)), ob.Field), nil
+	case OrderByPriority:
+		if len(ob.Values) == 0 {
+			return "", errOrderByValues
+		}
+
+		if err := validateColumn(Column(ob.Field)); err != nil {
+			return "", err
+		}
+
+		var b strings.Builder
+		b.WriteString(fmt.Sprintf("CASE %s", ob.Field))
+		for i, v := range ob.Values {
+			b.WriteString(fmt.Sprintf(" WHEN %s THEN %d", cond.Args.Add(v), i))
+		}
+		b.WriteString(fmt.Sprintf(" ELSE %d END", len(ob.Values)))
+
+		return b.String(), nil
+	case OrderByExpression:
+		if err := validateExpression(Expression(ob.Field), cfg); err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("(%s)", sqlbuilder.Escape(ob.Field)), nil
+	case OrderByRaw:
+		return (&Predicate{Raw: ob.Raw, Values: ob.Values}).rawToString(cond)
+	}
+
+	field, err := fieldColumn(ob.Field)
+	if err == nil {
+		return field, nil
+	}
+
+	// expressions like lower(c.name) or count(*) are still accepted as field
+	if validateExpression(Expression(ob.Field), cfg) != nil {
+		return "", err
+	}
+
+	return sqlbuilder.Escape(ob.Field), nil
 }
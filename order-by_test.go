@@ -0,0 +1,171 @@
+package visisql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderUnmarshalJSON(t *testing.T) {
+	type in struct {
+		json string
+	}
+
+	type out struct {
+		res Order
+		err error
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var tests = []*test{{
+		message: "order",
+		in:      &in{json: `"DESC"`},
+		out:     &out{res: OrderDesc},
+	}, {
+		message: "lowercase order",
+		in:      &in{json: `"asc"`},
+		out:     &out{res: OrderAsc},
+	}, {
+		message: "default order",
+		in:      &in{json: `""`},
+		out:     &out{res: ""},
+	}, {
+		message: "injection",
+		in:      &in{json: `"asc; drop table company"`},
+		out:     &out{err: fmt.Errorf("%w, got %q", errUnknownOrder, "asc; drop table company")},
+	}}
+
+	for _, test := range tests {
+		var ob OrderBy
+		err := json.Unmarshal([]byte(`{"field":"c.id","order":`+test.in.json+`}`), &ob)
+
+		if test.out.err != nil {
+			assert.True(t, errors.Is(err, errUnknownOrder), test.message)
+			assert.Equal(t, test.out.err.Error(), err.Error(), test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+		}
+
+		assert.Equal(t, test.out.res, ob.Order, test.message)
+	}
+}
+
+func TestOrderByToString(t *testing.T) {
+	type in struct {
+		orderBy *OrderBy
+	}
+
+	type out struct {
+		res  string
+		args []interface{}
+		err  error
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var tests = []*test{{
+		message: "nulls last",
+		in:      &in{orderBy: NewOrderBy("c.name", OrderDesc).WithNulls(NullsLast)},
+		out:     &out{res: "c.name DESC NULLS LAST"},
+	}, {
+		message: "collation without order",
+		in:      &in{orderBy: NewOrderBy("c.name", "").WithCollation("fr-FR-x-icu").WithNulls(NullsFirst)},
+		out:     &out{res: `c.name COLLATE "fr-FR-x-icu" NULLS FIRST`},
+	}, {
+		message: "position",
+		in:      &in{orderBy: NewPositionOrderBy("c.id", []interface{}{3, 1, 2}, OrderAsc)},
+		out:     &out{res: "array_position($0, c.id) ASC", args: []interface{}{pq.Array([]interface{}{3, 1, 2})}},
+	}, {
+		message: "priority",
+		in:      &in{orderBy: NewPriorityOrderBy("c.status", []interface{}{"active", "trial"}, OrderAsc)},
+		out:     &out{res: "CASE c.status WHEN $0 THEN 0 WHEN $1 THEN 1 ELSE 2 END ASC", args: []interface{}{"active", "trial"}},
+	}, {
+		message: "expression",
+		in:      &in{orderBy: NewExpressionOrderBy("coalesce(c.updated_at, c.created_at)", OrderDesc)},
+		out:     &out{res: "(coalesce(c.updated_at, c.created_at)) DESC"},
+	}, {
+		message: "raw",
+		in:      &in{orderBy: NewRawOrderBy("CASE WHEN c.status = ? THEN 0 END", OrderAsc, "active").WithNulls(NullsLast)},
+		out:     &out{res: "(CASE WHEN c.status = $0 THEN 0 END) ASC NULLS LAST", args: []interface{}{"active"}},
+	}, {
+		message: "invalid order",
+		in:      &in{orderBy: NewOrderBy("c.id", "ASC; DROP TABLE company")},
+		out:     &out{err: fmt.Errorf("%w, got %q", errUnknownOrder, "ASC; DROP TABLE company")},
+	}, {
+		message: "invalid nulls",
+		in:      &in{orderBy: NewOrderBy("c.id", OrderAsc).WithNulls("LAST; --")},
+		out:     &out{err: fmt.Errorf("%w, got %q", errUnknownNulls, "LAST; --")},
+	}, {
+		message: "invalid collation",
+		in:      &in{orderBy: NewOrderBy("c.name", OrderAsc).WithCollation(`C" desc, (select 1) "`)},
+		out:     &out{err: fmt.Errorf("%w, got %q", errInvalidCollation, `C" desc, (select 1) "`)},
+	}, {
+		message: "invalid expression",
+		in:      &in{orderBy: NewExpressionOrderBy("(select password from users)", OrderAsc)},
+		out:     &out{err: fmt.Errorf("%w, got %q at position %d", errInvalidExpression, "(select password from users)", 9)},
+	}, {
+		message: "invalid position field",
+		in:      &in{orderBy: NewPositionOrderBy("c.id)--", []interface{}{1}, OrderAsc)},
+		out:     &out{err: fmt.Errorf("%w, got %q", errInvalidColumn, "c.id)--")},
+	}, {
+		message: "priority without values",
+		in:      &in{orderBy: NewPriorityOrderBy("c.status", nil, OrderAsc)},
+		out:     &out{err: errOrderByValues},
+	}, {
+		message: "invalid field",
+		in:      &in{orderBy: NewOrderBy("c.name; drop table x --", OrderAsc)},
+		out:     &out{err: fmt.Errorf("%w, got %q", errInvalidColumn, "c.name; drop table x --")},
+	}, {
+		message: "invalid distance field",
+		in:      &in{orderBy: NewDistanceOrderBy("c.name) desc, (select 1", "POINT(2.35 48.85)", OrderAsc)},
+		out:     &out{err: fmt.Errorf("%w, got %q", errInvalidColumn, "c.name) desc, (select 1")},
+	}, {
+		message: "invalid rank field",
+		in:      &in{orderBy: NewFullTextRankOrderBy("c.name, 'x') desc, (select 1", "", "acme", OrderDesc)},
+		out:     &out{err: fmt.Errorf("%w, got %q", errInvalidColumn, "c.name, 'x') desc, (select 1")},
+	}, {
+		message: "expression field",
+		in:      &in{orderBy: NewOrderBy("count(*)", OrderDesc)},
+		out:     &out{res: "count(*) DESC"},
+	}, {
+		message: "function field",
+		in:      &in{orderBy: NewOrderBy("lower(c.name)", OrderAsc)},
+		out:     &out{res: "lower(c.name) ASC"},
+	}, {
+		message: "json path field",
+		in:      &in{orderBy: NewOrderBy("c.data->>'name'", OrderAsc)},
+		out:     &out{res: "c.data->>'name' ASC"},
+	}}
+
+	for _, test := range tests {
+		cond := &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond
+		res, err := test.in.orderBy.toString(cond, defaultBuildConfig)
+
+		assert.Equal(t, test.out.err, err, test.message)
+		assert.Equal(t, test.out.res, res, test.message)
+		if test.out.args != nil {
+			var format []string
+			for i := range test.out.args {
+				format = append(format, fmt.Sprintf("$%d", i))
+			}
+
+			_, args := cond.Args.CompileWithFlavor(strings.Join(format, " "), sqlbuilder.PostgreSQL)
+			assert.Equal(t, test.out.args, args, test.message)
+		}
+	}
+}
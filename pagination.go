@@ -1,10 +1,71 @@
 package visisql
 
+import "errors"
+
+var errPaginationNegative = errors.New("pagination values must not be negative")
+var errPaginationMode = errors.New("pagination must use either start and limit or page and page size")
+var errPaginationPageSize = errors.New("pagination page size must be positive when page is set")
+
 type Pagination struct {
-	Start int `json:"start"`
-	Limit int `json:"limit"`
+	Start    int `json:"start"`
+	Limit    int `json:"limit"`
+	Page     int `json:"page,omitempty"`
+	PageSize int `json:"pageSize,omitempty"`
 }
 
 func NewPagination(start, limit int) *Pagination {
 	return &Pagination{Start: start, Limit: limit}
 }
+
+// NewPagePagination paginates by page number, starting at 1, and page size.
+func NewPagePagination(page, pageSize int) *Pagination {
+	return &Pagination{Page: page, PageSize: pageSize}
+}
+
+func (p *Pagination) paged() bool {
+	return p.Page != 0 || p.PageSize != 0
+}
+
+func (p *Pagination) offset() int {
+	if !p.paged() {
+		return p.Start
+	}
+
+	if p.Page <= 1 {
+		return 0
+	}
+
+	return (p.Page - 1) * p.PageSize
+}
+
+func (p *Pagination) limit() int {
+	if p.paged() {
+		return p.PageSize
+	}
+
+	return p.Limit
+}
+
+func (p *Pagination) setLimit(limit int) {
+	if p.paged() {
+		p.PageSize = limit
+	} else {
+		p.Limit = limit
+	}
+}
+
+func (p *Pagination) validate() error {
+	if p.Start < 0 || p.Limit < 0 || p.Page < 0 || p.PageSize < 0 {
+		return errPaginationNegative
+	}
+
+	if p.paged() && (p.Start != 0 || p.Limit != 0) {
+		return errPaginationMode
+	}
+
+	if p.Page > 0 && p.PageSize == 0 {
+		return errPaginationPageSize
+	}
+
+	return nil
+}
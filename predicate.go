@@ -1,11 +1,13 @@
 package visisql
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/huandu/go-sqlbuilder"
+	"github.com/lib/pq"
 )
 
 var errOperatorEqual = errors.New("predicate must have only one value when operator is equal")
@@ -13,7 +15,21 @@ var errOperatorLike = errors.New("predicate must have only one value when operat
 var errOperatorIsNull = errors.New("predicate should not have value(s) when operator is null")
 var errOperatorLessThan = errors.New("predicate must have only one value when operator is less than")
 var errOperatorGreaterThan = errors.New("predicate must have only one value when operator is greater than")
+var errOperatorNotEqual = errors.New("predicate must have only one value when operator is not equal")
+var errOperatorLessThanOrEqual = errors.New("predicate must have only one value when operator is less than or equal")
+var errOperatorGreaterThanOrEqual = errors.New("predicate must have only one value when operator is greater than or equal")
+var errOperatorInArray = errors.New("predicate must not have value funcs when operator is in with array")
+var errOperatorJSONContains = errors.New("predicate must have only one value when operator is json contains")
+var errOperatorJSONHasKey = errors.New("predicate must have only one string value when operator is json has key")
+var errOperatorJSONHasKeys = errors.New("predicate must have at least one string value when operator is json has any keys or json has all keys")
+var errOperatorJSONPath = errors.New("predicate must have only one string value when operator is json path exists or json path match")
+var errOperatorSimilar = errors.New("predicate must have a value and an optional threshold when operator is similar or word similar")
+var errQuantifier = errors.New("predicate quantifier must be ANY or ALL and operator must be a comparison other than like")
 var errOperatorBetween = errors.New("predicate must have two values when operator is between")
+var errOperatorInSubQuery = errors.New("predicate must not have values when operator is in with a subquery")
+var errOperatorExists = errors.New("predicate must have a subquery and no value when operator is exists")
+var errOperatorNotExists = errors.New("predicate must have a subquery and no value when operator is not exists")
+var errUnknownOperator = errors.New("operator is unknown")
 
 type Operator string
 
@@ -25,19 +41,255 @@ const (
 	OperatorLessThan    Operator = "LESS THAN"
 	OperatorGreaterThan Operator = "GREATER THAN"
 	OperatorBetween     Operator = "BETWEEN"
+	OperatorTimeRange   Operator = "TIME RANGE"
+	OperatorExists      Operator = "EXISTS"
+	OperatorNotExists   Operator = "NOT EXISTS"
+	OperatorRaw         Operator = "RAW"
+
+	OperatorNotEqual           Operator = "NOT EQUALS"
+	OperatorLessThanOrEqual    Operator = "LESS THAN OR EQUAL"
+	OperatorGreaterThanOrEqual Operator = "GREATER THAN OR EQUAL"
+
+	OperatorContains    Operator = "CONTAINS"
+	OperatorContainedBy Operator = "CONTAINED BY"
+	OperatorOverlaps    Operator = "OVERLAPS"
+
+	OperatorJSONContains   Operator = "JSON CONTAINS"
+	OperatorJSONHasKey     Operator = "JSON HAS KEY"
+	OperatorJSONHasAnyKeys Operator = "JSON HAS ANY KEYS"
+	OperatorJSONHasAllKeys Operator = "JSON HAS ALL KEYS"
+	OperatorJSONPathExists Operator = "JSON PATH EXISTS"
+	OperatorJSONPathMatch  Operator = "JSON PATH MATCH"
+
+	OperatorFullText      Operator = "FULL TEXT"
+	OperatorTSVectorMatch Operator = "TSVECTOR MATCH"
+
+	OperatorSimilar     Operator = "SIMILAR"
+	OperatorWordSimilar Operator = "WORD SIMILAR"
+
+	OperatorRangeOverlaps    Operator = "RANGE OVERLAPS"
+	OperatorRangeContains    Operator = "RANGE CONTAINS"
+	OperatorRangeContainedBy Operator = "RANGE CONTAINED BY"
+	OperatorRangeAdjacent    Operator = "RANGE ADJACENT"
+
+	OperatorGeoDWithin    Operator = "GEO DWITHIN"
+	OperatorGeoIntersects Operator = "GEO INTERSECTS"
+	OperatorGeoContains   Operator = "GEO CONTAINS"
+)
+
+var operators = map[Operator]bool{
+	OperatorIn:                 true,
+	OperatorEqual:              true,
+	OperatorLike:               true,
+	OperatorIsNull:             true,
+	OperatorLessThan:           true,
+	OperatorGreaterThan:        true,
+	OperatorBetween:            true,
+	OperatorTimeRange:          true,
+	OperatorExists:             true,
+	OperatorNotExists:          true,
+	OperatorRaw:                true,
+	OperatorNotEqual:           true,
+	OperatorLessThanOrEqual:    true,
+	OperatorGreaterThanOrEqual: true,
+	OperatorContains:           true,
+	OperatorContainedBy:        true,
+	OperatorOverlaps:           true,
+	OperatorJSONContains:       true,
+	OperatorJSONHasKey:         true,
+	OperatorJSONHasAnyKeys:     true,
+	OperatorJSONHasAllKeys:     true,
+	OperatorJSONPathExists:     true,
+	OperatorJSONPathMatch:      true,
+	OperatorFullText:           true,
+	OperatorTSVectorMatch:      true,
+	OperatorSimilar:            true,
+	OperatorWordSimilar:        true,
+	OperatorRangeOverlaps:      true,
+	OperatorRangeContains:      true,
+	OperatorRangeContainedBy:   true,
+	OperatorRangeAdjacent:      true,
+	OperatorGeoDWithin:         true,
+	OperatorGeoIntersects:      true,
+	OperatorGeoContains:        true,
+}
+
+// serverOperators need a subquery or a raw expression built on the server,
+// they are neither decoded nor described by JSONSchema.
+var serverOperators = map[Operator]bool{
+	OperatorExists:    true,
+	OperatorNotExists: true,
+	OperatorRaw:       true,
+}
+
+var operatorAliases = map[string]Operator{
+	"eq":   OperatorEqual,
+	"=":    OperatorEqual,
+	"==":   OperatorEqual,
+	"ne":   OperatorNotEqual,
+	"neq":  OperatorNotEqual,
+	"!=":   OperatorNotEqual,
+	"<>":   OperatorNotEqual,
+	"lt":   OperatorLessThan,
+	"<":    OperatorLessThan,
+	"lte":  OperatorLessThanOrEqual,
+	"le":   OperatorLessThanOrEqual,
+	"<=":   OperatorLessThanOrEqual,
+	"gt":   OperatorGreaterThan,
+	">":    OperatorGreaterThan,
+	"gte":  OperatorGreaterThanOrEqual,
+	"ge":   OperatorGreaterThanOrEqual,
+	">=":   OperatorGreaterThanOrEqual,
+	"null": OperatorIsNull,
+}
+
+// UnmarshalText accepts operators, case insensitively, and aliases like eq, gt or >=.
+// Server operators (exists, not exists and raw) are rejected.
+func (o *Operator) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if op, ok := operatorAliases[strings.ToLower(s)]; ok {
+		*o = op
+		return nil
+	}
+
+	if op := Operator(strings.ToUpper(s)); operators[op] && !serverOperators[op] {
+		*o = op
+		return nil
+	}
+
+	return fmt.Errorf("%w, got %q", errUnknownOperator, string(text))
+}
+
+func (o *Operator) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("%w, got %s", errUnknownOperator, b)
+	}
+
+	return o.UnmarshalText([]byte(s))
+}
+
+var rangeOperators = map[Operator]string{
+	OperatorRangeOverlaps:    "&&",
+	OperatorRangeContains:    "@>",
+	OperatorRangeContainedBy: "<@",
+	OperatorRangeAdjacent:    "-|-",
+}
+
+type valuesRule struct {
+	min     int
+	max     int
+	strings bool
+	err     error
+}
+
+var operatorValuesRules = map[Operator]*valuesRule{
+	OperatorEqual:              {min: 1, max: 1, err: errOperatorEqual},
+	OperatorLike:               {min: 1, max: 1, err: errOperatorLike},
+	OperatorIsNull:             {min: 0, max: 0, err: errOperatorIsNull},
+	OperatorLessThan:           {min: 1, max: 1, err: errOperatorLessThan},
+	OperatorGreaterThan:        {min: 1, max: 1, err: errOperatorGreaterThan},
+	OperatorBetween:            {min: 2, max: 2, err: errOperatorBetween},
+	OperatorTimeRange:          {min: 2, max: 2, err: errOperatorTimeRange},
+	OperatorNotEqual:           {min: 1, max: 1, err: errOperatorNotEqual},
+	OperatorLessThanOrEqual:    {min: 1, max: 1, err: errOperatorLessThanOrEqual},
+	OperatorGreaterThanOrEqual: {min: 1, max: 1, err: errOperatorGreaterThanOrEqual},
+	OperatorJSONContains:       {min: 1, max: 1, err: errOperatorJSONContains},
+	OperatorJSONHasKey:         {min: 1, max: 1, strings: true, err: errOperatorJSONHasKey},
+	OperatorJSONHasAnyKeys:     {min: 1, max: -1, strings: true, err: errOperatorJSONHasKeys},
+	OperatorJSONHasAllKeys:     {min: 1, max: -1, strings: true, err: errOperatorJSONHasKeys},
+	OperatorJSONPathExists:     {min: 1, max: 1, strings: true, err: errOperatorJSONPath},
+	OperatorJSONPathMatch:      {min: 1, max: 1, strings: true, err: errOperatorJSONPath},
+	OperatorFullText:           {min: 1, max: 1, strings: true, err: errOperatorFullText},
+	OperatorTSVectorMatch:      {min: 1, max: 1, strings: true, err: errOperatorFullText},
+	OperatorSimilar:            {min: 1, max: 2, err: errOperatorSimilar},
+	OperatorWordSimilar:        {min: 1, max: 2, err: errOperatorSimilar},
+	OperatorRangeOverlaps:      {min: 1, max: 1, err: errOperatorRange},
+	OperatorRangeContains:      {min: 1, max: 1, err: errOperatorRange},
+	OperatorRangeContainedBy:   {min: 1, max: 1, err: errOperatorRange},
+	OperatorRangeAdjacent:      {min: 1, max: 1, err: errOperatorRange},
+	OperatorGeoDWithin:         {min: 2, max: 2, err: errOperatorGeoDWithin},
+	OperatorGeoIntersects:      {min: 1, max: 1, err: errOperatorGeo},
+	OperatorGeoContains:        {min: 1, max: 1, err: errOperatorGeo},
+}
+
+type Quantifier string
+
+const (
+	QuantifierAny Quantifier = "ANY"
+	QuantifierAll Quantifier = "ALL"
 )
 
+var commutedComparisons = map[string]string{
+	"=":  "=",
+	"<>": "<>",
+	"<":  ">",
+	">":  "<",
+	"<=": ">=",
+	">=": "<=",
+}
+
 type Predicate struct {
 	Field    string        `json:"field"`
 	Operator Operator      `json:"operator"`
 	Values   []interface{} `json:"values"`
+	Query    *SelectQuery  `json:"-"`
 	Funcs    []string
+
+	Functions []*Func `json:"functions,omitempty"`
+
+	Array      bool       `json:"array,omitempty"`
+	Quantifier Quantifier `json:"quantifier,omitempty"`
+	Config     string     `json:"config,omitempty"`
+	Matching   Matching   `json:"matching,omitempty"`
+
+	Raw   string                 `json:"-"`
+	Named map[string]interface{} `json:"-"`
 }
 
 func NewPredicate(field string, operator Operator, values []interface{}, funcs ...string) *Predicate {
 	return &Predicate{Field: field, Operator: operator, Values: values, Funcs: funcs}
 }
 
+func NewFullTextPredicate(field string, config string, query string) *Predicate {
+	return &Predicate{Field: field, Operator: OperatorFullText, Values: []interface{}{query}, Config: config}
+}
+
+func NewTSVectorPredicate(field string, config string, query string) *Predicate {
+	return &Predicate{Field: field, Operator: OperatorTSVectorMatch, Values: []interface{}{query}, Config: config}
+}
+
+func NewSubQueryPredicate(field string, operator Operator, query *SelectQuery, funcs ...string) *Predicate {
+	return &Predicate{Field: field, Operator: operator, Query: query, Funcs: funcs}
+}
+
+func NewExistsPredicate(query *SelectQuery) *Predicate {
+	return &Predicate{Operator: OperatorExists, Query: query}
+}
+
+func NewNotExistsPredicate(query *SelectQuery) *Predicate {
+	return &Predicate{Operator: OperatorNotExists, Query: query}
+}
+
+func (p *Predicate) WithFuncs(funcs ...*Func) *Predicate {
+	p.Functions = append(p.Functions, funcs...)
+	return p
+}
+
+func (p *Predicate) AsArray() *Predicate {
+	p.Array = true
+	return p
+}
+
+func (p *Predicate) WithQuantifier(quantifier Quantifier) *Predicate {
+	p.Quantifier = quantifier
+	return p
+}
+
 func (p *Predicate) IsOperator(operator Operator) bool {
 	return p.Operator == operator
 }
@@ -51,56 +303,367 @@ func (p *Predicate) wrapFuncs(val string) string {
 	return fmt.Sprintf(s, val)
 }
 
-func predicatesToStrings(predicates [][]*Predicate, cond *sqlbuilder.Cond) ([]string, error) {
+func (p *Predicate) applyFuncs(cond *sqlbuilder.Cond, cfg *buildConfig, val string, side FuncSide) string {
+	val = p.wrapFuncs(val)
+	for _, f := range p.Functions {
+		if f.appliesTo(side) {
+			val = f.wrap(p, cond, val)
+		}
+	}
+
+	return p.wrapMatching(cfg, val)
+}
+
+func (p *Predicate) fieldExpr(cond *sqlbuilder.Cond, cfg *buildConfig) string {
+	return p.applyFuncs(cond, cfg, p.field(), FuncSideField)
+}
+
+func (p *Predicate) valueExpr(cond *sqlbuilder.Cond, cfg *buildConfig, v interface{}) string {
+	return p.applyFuncs(cond, cfg, p.arg(cond, v), FuncSideValue)
+}
+
+func (p *Predicate) hasValueFuncs() bool {
+	if len(p.Funcs) > 0 {
+		return true
+	}
+
+	for _, f := range p.Functions {
+		if f.appliesTo(FuncSideValue) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Predicate) validateFuncs(cfg *buildConfig) error {
+	for _, f := range p.Funcs {
+		if err := validateFuncName(f, cfg); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range p.Functions {
+		if err := f.validate(cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Predicate) arg(cond *sqlbuilder.Cond, v interface{}) string {
+	switch r := v.(type) {
+	case Column:
+		return sqlbuilder.Escape(string(r))
+	case Expression:
+		return fmt.Sprintf("(%s)", sqlbuilder.Escape(string(r)))
+	}
+
+	return cond.Args.Add(v)
+}
+
+func (p *Predicate) field() string {
+	if isJSONPath(p.Field) {
+		field, _ := parseJSONPath(p.Field)
+		return field
+	}
+
+	return sqlbuilder.Escape(p.Field)
+}
+
+func (p *Predicate) validateField() error {
+	if isJSONPath(p.Field) {
+		_, err := parseJSONPath(p.Field)
+		return err
+	}
+
+	return nil
+}
+
+func (p *Predicate) stringValues() bool {
+	for _, v := range p.Values {
+		if _, ok := v.(string); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p *Predicate) validateValues() error {
+	r, ok := operatorValuesRules[p.Operator]
+	if !ok {
+		return nil
+	}
+
+	if len(p.Values) < r.min || (r.max >= 0 && len(p.Values) > r.max) || (r.strings && !p.stringValues()) {
+		return r.err
+	}
+
+	return nil
+}
+
+func (p *Predicate) comparison(cond *sqlbuilder.Cond, cfg *buildConfig, op string) string {
+	field := p.fieldExpr(cond, cfg)
+	value := p.valueExpr(cond, cfg, p.Values[0])
+
+	if p.Quantifier != "" {
+		return fmt.Sprintf("%s %s %s(%s)", value, commutedComparisons[op], p.Quantifier, field)
+	}
+
+	return fmt.Sprintf("%s %s %s", field, op, value)
+}
+
+func (p *Predicate) validateQuantifier() error {
+	if p.Quantifier == "" {
+		return nil
+	}
+
+	if p.Quantifier != QuantifierAny && p.Quantifier != QuantifierAll {
+		return errQuantifier
+	}
+
+	switch p.Operator {
+	case OperatorEqual, OperatorNotEqual, OperatorLessThan, OperatorLessThanOrEqual, OperatorGreaterThan, OperatorGreaterThanOrEqual:
+		return nil
+	}
+
+	return errQuantifier
+}
+
+func (p *Predicate) validateReferences(cfg *buildConfig) error {
+	values := append([]interface{}(nil), p.Values...)
+	for _, v := range p.Named {
+		values = append(values, v)
+	}
+
+	for _, v := range values {
+		if err := validateReference(v, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateReference(v interface{}, cfg *buildConfig) error {
+	switch r := v.(type) {
+	case Column:
+		return validateColumn(r)
+	case Expression:
+		return validateExpression(r, cfg)
+	}
+
+	return nil
+}
+
+func (p *Predicate) subQuery(cond *sqlbuilder.Cond, cfg *buildConfig) (string, error) {
+	builder, err := newQueryBuilder(p.Query, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	return cond.Args.Add(builder), nil
+}
+
+func predicatesToStrings(predicates [][]*Predicate, cond *sqlbuilder.Cond, cfg *buildConfig) ([]string, error) {
 	var andExprs []string
 	for _, pAnd := range predicates {
 
 		var orExprs []string
 		for _, pOr := range pAnd {
-			if pOr.IsOperator(OperatorIn) {
+			if !operators[pOr.Operator] {
+				return nil, fmt.Errorf("visisql predicates: %w", &QueryError{fmt.Errorf("%w, got %q", errUnknownOperator, pOr.Operator)})
+			}
+			if err := pOr.validateReferences(cfg); err != nil {
+				return nil, fmt.Errorf("visisql predicates: %w", &QueryError{err})
+			}
+			if err := pOr.validateFuncs(cfg); err != nil {
+				return nil, fmt.Errorf("visisql predicates: %w", &QueryError{err})
+			}
+			if err := pOr.validateQuantifier(); err != nil {
+				return nil, fmt.Errorf("visisql predicates: %w", &QueryError{err})
+			}
+			if err := pOr.validateMatching(cfg); err != nil {
+				return nil, fmt.Errorf("visisql predicates: %w", &QueryError{err})
+			}
+			if err := pOr.validateField(); err != nil {
+				return nil, fmt.Errorf("visisql predicates: %w", &QueryError{err})
+			}
+			if err := pOr.validateValues(); err != nil {
+				return nil, fmt.Errorf("visisql predicates: %w", &QueryError{err})
+			}
+
+			if pOr.IsOperator(OperatorIn) && pOr.Query != nil {
+				if len(pOr.Values) > 0 {
+					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{errOperatorInSubQuery})
+				}
+
+				sq, err := pOr.subQuery(cond, cfg)
+				if err != nil {
+					return nil, err
+				}
+
+				orExprs = append(orExprs, fmt.Sprintf("%s IN (%s)", pOr.fieldExpr(cond, cfg), sq))
+			} else if pOr.IsOperator(OperatorIn) && pOr.Array {
+				if pOr.hasValueFuncs() {
+					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{errOperatorInArray})
+				}
+
+				orExprs = append(orExprs, fmt.Sprintf("%s = ANY(%s)", pOr.fieldExpr(cond, cfg), cond.Args.Add(pq.Array(pOr.Values))))
+			} else if pOr.IsOperator(OperatorIn) {
 				vs := make([]string, 0, len(pOr.Values))
 
 				for _, v := range pOr.Values {
-					vs = append(vs, pOr.wrapFuncs(cond.Args.Add(v)))
+					vs = append(vs, pOr.valueExpr(cond, cfg, v))
 				}
 
-				orExprs = append(orExprs, fmt.Sprintf("%s IN (%s)", pOr.wrapFuncs(sqlbuilder.Escape(pOr.Field)), strings.Join(vs, ", ")))
+				orExprs = append(orExprs, fmt.Sprintf("%s IN (%s)", pOr.fieldExpr(cond, cfg), strings.Join(vs, ", ")))
 			}
 			if pOr.IsOperator(OperatorEqual) {
-				if len(pOr.Values) != 1 {
-					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{errOperatorEqual})
-				}
-				orExprs = append(orExprs, fmt.Sprintf("%s = %s", pOr.wrapFuncs(sqlbuilder.Escape(pOr.Field)), pOr.wrapFuncs(cond.Args.Add(pOr.Values[0]))))
+				orExprs = append(orExprs, pOr.comparison(cond, cfg, "="))
 			}
 			if pOr.IsOperator(OperatorLike) {
-				if len(pOr.Values) != 1 {
-					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{errOperatorLike})
-				}
-				orExprs = append(orExprs, fmt.Sprintf("%s LIKE %s", pOr.wrapFuncs(sqlbuilder.Escape(pOr.Field)), pOr.wrapFuncs(cond.Args.Add(pOr.Values[0]))))
+				orExprs = append(orExprs, pOr.comparison(cond, cfg, pOr.likeOperator(cfg)))
 			}
 			if pOr.IsOperator(OperatorIsNull) {
-				if len(pOr.Values) > 0 {
-					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{errOperatorIsNull})
-				}
-				orExprs = append(orExprs, fmt.Sprintf("%s IS NULL", pOr.wrapFuncs(sqlbuilder.Escape(pOr.Field))))
+				orExprs = append(orExprs, fmt.Sprintf("%s IS NULL", pOr.fieldExpr(cond, cfg)))
 			}
 			if pOr.IsOperator(OperatorLessThan) {
-				if len(pOr.Values) != 1 {
-					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{errOperatorLessThan})
-				}
-				orExprs = append(orExprs, fmt.Sprintf("%s < %s", pOr.wrapFuncs(sqlbuilder.Escape(pOr.Field)), pOr.wrapFuncs(cond.Args.Add(pOr.Values[0]))))
+				orExprs = append(orExprs, pOr.comparison(cond, cfg, "<"))
 			}
 			if pOr.IsOperator(OperatorGreaterThan) {
-				if len(pOr.Values) != 1 {
-					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{errOperatorGreaterThan})
-				}
-				orExprs = append(orExprs, fmt.Sprintf("%s > %s", pOr.wrapFuncs(sqlbuilder.Escape(pOr.Field)), pOr.wrapFuncs(cond.Args.Add(pOr.Values[0]))))
+				orExprs = append(orExprs, pOr.comparison(cond, cfg, ">"))
+			}
+			if pOr.IsOperator(OperatorNotEqual) {
+				orExprs = append(orExprs, pOr.comparison(cond, cfg, "<>"))
+			}
+			if pOr.IsOperator(OperatorLessThanOrEqual) {
+				orExprs = append(orExprs, pOr.comparison(cond, cfg, "<="))
+			}
+			if pOr.IsOperator(OperatorGreaterThanOrEqual) {
+				orExprs = append(orExprs, pOr.comparison(cond, cfg, ">="))
 			}
 			if pOr.IsOperator(OperatorBetween) {
-				if len(pOr.Values) != 2 {
-					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{errOperatorBetween})
+				orExprs = append(orExprs, fmt.Sprintf("%s BETWEEN %s AND %s", pOr.fieldExpr(cond, cfg), pOr.valueExpr(cond, cfg, pOr.Values[0]), pOr.valueExpr(cond, cfg, pOr.Values[1])))
+			}
+			if pOr.IsOperator(OperatorTimeRange) {
+				field := pOr.fieldExpr(cond, cfg)
+				orExprs = append(orExprs, fmt.Sprintf("%s >= %s AND %s < %s", field, pOr.valueExpr(cond, cfg, pOr.Values[0]), field, pOr.valueExpr(cond, cfg, pOr.Values[1])))
+			}
+			if pOr.IsOperator(OperatorContains) {
+				orExprs = append(orExprs, fmt.Sprintf("%s @> %s", pOr.fieldExpr(cond, cfg), cond.Args.Add(pq.Array(pOr.Values))))
+			}
+			if pOr.IsOperator(OperatorContainedBy) {
+				orExprs = append(orExprs, fmt.Sprintf("%s <@ %s", pOr.fieldExpr(cond, cfg), cond.Args.Add(pq.Array(pOr.Values))))
+			}
+			if pOr.IsOperator(OperatorOverlaps) {
+				orExprs = append(orExprs, fmt.Sprintf("%s && %s", pOr.fieldExpr(cond, cfg), cond.Args.Add(pq.Array(pOr.Values))))
+			}
+			if pOr.IsOperator(OperatorJSONContains) {
+				b, err := json.Marshal(pOr.Values[0])
+				if err != nil {
+					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{err})
+				}
+				orExprs = append(orExprs, fmt.Sprintf("%s @> %s::jsonb", pOr.fieldExpr(cond, cfg), cond.Args.Add(string(b))))
+			}
+			if pOr.IsOperator(OperatorJSONHasKey) {
+				orExprs = append(orExprs, fmt.Sprintf("%s ? %s", pOr.fieldExpr(cond, cfg), cond.Args.Add(pOr.Values[0])))
+			}
+			if pOr.IsOperator(OperatorJSONHasAnyKeys) || pOr.IsOperator(OperatorJSONHasAllKeys) {
+				op := "?|"
+				if pOr.IsOperator(OperatorJSONHasAllKeys) {
+					op = "?&"
+				}
+				orExprs = append(orExprs, fmt.Sprintf("%s %s %s::text[]", pOr.fieldExpr(cond, cfg), op, cond.Args.Add(pq.Array(pOr.Values))))
+			}
+			if pOr.IsOperator(OperatorJSONPathExists) || pOr.IsOperator(OperatorJSONPathMatch) {
+				op := "@?"
+				if pOr.IsOperator(OperatorJSONPathMatch) {
+					op = "@@"
+				}
+				orExprs = append(orExprs, fmt.Sprintf("%s %s %s::jsonpath", pOr.fieldExpr(cond, cfg), op, cond.Args.Add(pOr.Values[0])))
+			}
+			if pOr.IsOperator(OperatorFullText) || pOr.IsOperator(OperatorTSVectorMatch) {
+				config, err := textSearchConfig(pOr.Config)
+				if err != nil {
+					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{err})
 				}
-				orExprs = append(orExprs, fmt.Sprintf("%s BETWEEN %s AND %s", pOr.wrapFuncs(sqlbuilder.Escape(pOr.Field)), pOr.wrapFuncs(cond.Args.Add(pOr.Values[0])), pOr.wrapFuncs(cond.Args.Add(pOr.Values[1]))))
+				orExprs = append(orExprs, fmt.Sprintf("%s @@ %s", tsVector(config, pOr.fieldExpr(cond, cfg), pOr.IsOperator(OperatorTSVectorMatch)), tsQuery(config, cond.Args.Add(pOr.Values[0]))))
+			}
+			if pOr.IsOperator(OperatorSimilar) || pOr.IsOperator(OperatorWordSimilar) {
+				field, value := pOr.fieldExpr(cond, cfg), pOr.valueExpr(cond, cfg, pOr.Values[0])
+				if len(pOr.Values) == 2 && pOr.IsOperator(OperatorSimilar) {
+					orExprs = append(orExprs, fmt.Sprintf("similarity(%s, %s) >= %s", field, value, cond.Args.Add(pOr.Values[1])))
+				} else if len(pOr.Values) == 2 {
+					orExprs = append(orExprs, fmt.Sprintf("word_similarity(%s, %s) >= %s", value, field, cond.Args.Add(pOr.Values[1])))
+				} else if pOr.IsOperator(OperatorSimilar) {
+					orExprs = append(orExprs, fmt.Sprintf("%s %% %s", field, value))
+				} else {
+					orExprs = append(orExprs, fmt.Sprintf("%s <%% %s", value, field))
+				}
+			}
+			if op, ok := rangeOperators[pOr.Operator]; ok {
+				r, err := rangeValue(pOr.Values[0])
+				if err != nil {
+					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{err})
+				}
+
+				if _, err := r.Value(); err != nil {
+					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{err})
+				}
+				orExprs = append(orExprs, fmt.Sprintf("%s %s %s", pOr.fieldExpr(cond, cfg), op, cond.Args.Add(r)))
+			}
+			if pOr.IsOperator(OperatorGeoDWithin) {
+				g, err := geometryValue(pOr.Values[0])
+				if err != nil {
+					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{err})
+				}
+				orExprs = append(orExprs, fmt.Sprintf("ST_DWithin(%s, %s, %s)", pOr.field(), cond.Args.Add(g), pOr.arg(cond, pOr.Values[1])))
+			}
+			if pOr.IsOperator(OperatorGeoIntersects) || pOr.IsOperator(OperatorGeoContains) {
+				g, err := geometryValue(pOr.Values[0])
+				if err != nil {
+					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{err})
+				}
+
+				fn := "ST_Intersects"
+				if pOr.IsOperator(OperatorGeoContains) {
+					fn = "ST_Contains"
+				}
+				orExprs = append(orExprs, fmt.Sprintf("%s(%s, %s)", fn, pOr.field(), cond.Args.Add(g)))
+			}
+			if pOr.IsOperator(OperatorRaw) {
+				raw, err := pOr.rawToString(cond)
+				if err != nil {
+					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{err})
+				}
+				orExprs = append(orExprs, raw)
+			}
+			if pOr.IsOperator(OperatorExists) {
+				if pOr.Query == nil || len(pOr.Values) > 0 {
+					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{errOperatorExists})
+				}
+
+				sq, err := pOr.subQuery(cond, cfg)
+				if err != nil {
+					return nil, err
+				}
+
+				orExprs = append(orExprs, fmt.Sprintf("EXISTS (%s)", sq))
+			}
+			if pOr.IsOperator(OperatorNotExists) {
+				if pOr.Query == nil || len(pOr.Values) > 0 {
+					return nil, fmt.Errorf("visisql predicates: %w", &QueryError{errOperatorNotExists})
+				}
+
+				sq, err := pOr.subQuery(cond, cfg)
+				if err != nil {
+					return nil, err
+				}
+
+				orExprs = append(orExprs, fmt.Sprintf("NOT EXISTS (%s)", sq))
 			}
 		}
 
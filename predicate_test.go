@@ -1,8 +1,11 @@
 package visisql
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/huandu/go-sqlbuilder"
 	"github.com/stretchr/testify/assert"
@@ -75,6 +78,9 @@ func TestPredicatesToString(t *testing.T) {
 		out     *out
 	}
 
+	datePart, err := NewDatePartPredicate("c.created_at", DatePartDayOfWeek, OperatorEqual, 1, nil)
+	assert.Nil(t, err)
+
 	var tests = []*test{{
 		message: "invalid values length with equals operator",
 		in: &in{
@@ -87,6 +93,382 @@ func TestPredicatesToString(t *testing.T) {
 			res: nil,
 			err: &QueryError{errOperatorEqual},
 		},
+	}, {
+		message: "unknown operator",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("table.id", Operator("GREATER"), []interface{}{1}),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{fmt.Errorf("%w, got %q", errUnknownOperator, "GREATER")},
+		},
+	}, {
+		message: "in operator with values and subquery",
+		in: &in{
+			predicates: [][]*Predicate{{
+				{Field: "table.id", Operator: OperatorIn, Values: []interface{}{1}, Query: NewSelectQuery([]string{"id"}, "other")},
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{errOperatorInSubQuery},
+		},
+	}, {
+		message: "exists operator without subquery",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("", OperatorExists, nil),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{errOperatorExists},
+		},
+	}, {
+		message: "not exists operator with values",
+		in: &in{
+			predicates: [][]*Predicate{{
+				{Operator: OperatorNotExists, Values: []interface{}{1}, Query: NewSelectQuery([]string{"id"}, "other")},
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{errOperatorNotExists},
+		},
+	}, {
+		message: "invalid column value",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("table.id", OperatorEqual, []interface{}{Column("other.id; drop table other")}),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{fmt.Errorf("%w, got %q", errInvalidColumn, "other.id; drop table other")},
+		},
+	}, {
+		message: "invalid expression value",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("table.total", OperatorEqual, []interface{}{Expression("pg_sleep(10)")}),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{fmt.Errorf("%w, got %q", errExpressionFunc, "pg_sleep")},
+		},
+	}, {
+		message: "column and expression values",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("u.updated_at", OperatorGreaterThan, []interface{}{Column("u.created_at")}),
+			}, {
+				NewPredicate("a.total", OperatorEqual, []interface{}{Column("b.total")}),
+				NewPredicate("a.total", OperatorNotEqual, []interface{}{Expression("b.total + b.fee")}),
+			}, {
+				NewPredicate("a.total", OperatorLessThanOrEqual, []interface{}{Expression("b.total * 2")}),
+				NewPredicate("a.total", OperatorGreaterThanOrEqual, []interface{}{10}),
+			}, {
+				NewPredicate("a.id", OperatorIn, []interface{}{Column("b.id"), 2}),
+			}, {
+				NewPredicate("a.created_at", OperatorBetween, []interface{}{Column("b.start_at"), Column("b.end_at")}),
+			}, {
+				NewPredicate("a.name", OperatorLike, []interface{}{Column("b.name")}, "lower"),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: []string{
+				"( u.updated_at > u.created_at )",
+				"( a.total = b.total OR a.total <> (b.total + b.fee) )",
+				"( a.total <= (b.total * 2) OR a.total >= $0 )",
+				"( a.id IN (b.id, $1) )",
+				"( a.created_at BETWEEN b.start_at AND b.end_at )",
+				"( lower(a.name) LIKE lower(b.name) )",
+			},
+			err: nil,
+		},
+	}, {
+		message: "raw predicates",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("c.id", OperatorEqual, []interface{}{1}),
+				Raw("c.tags && ?", []string{"a", "b"}),
+			}, {
+				Raw("c.data ?? 'key' AND c.name <> '?' AND c.created_at::date = ?", "2020-01-01"),
+			}, {
+				RawNamed("c.price BETWEEN :min AND :max OR c.discount > :min", map[string]interface{}{"min": 10, "max": 20}),
+			}, {
+				Raw(`c.name <> E'it\'s?' AND c."why?" = ? AND c.note <> 'it''s?'`, "because"),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: []string{
+				"( c.id = $0 OR (c.tags && $1) )",
+				"( (c.data ? 'key' AND c.name <> '?' AND c.created_at::date = $2) )",
+				"( (c.price BETWEEN $3 AND $4 OR c.discount > $3) )",
+				`( (c.name <> E'it\'s?' AND c."why?" = $5 AND c.note <> 'it''s?') )`,
+			},
+			err: nil,
+		},
+	}, {
+		message: "raw predicate with missing values",
+		in: &in{
+			predicates: [][]*Predicate{{
+				Raw("c.id = ? OR c.parent_id = ?", 1),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{errRawArgs},
+		},
+	}, {
+		message: "raw predicate with missing named value",
+		in: &in{
+			predicates: [][]*Predicate{{
+				RawNamed("c.id = :id", map[string]interface{}{}),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{fmt.Errorf("%w, got %q", errRawNamedArg, "id")},
+		},
+	}, {
+		message: "array operators and quantifiers",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("c.id", OperatorIn, []interface{}{1, 2, 3}).AsArray(),
+			}, {
+				NewPredicate("c.tags", OperatorContains, []interface{}{"a", "b"}),
+				NewPredicate("c.tags", OperatorContainedBy, []interface{}{"a", "b"}),
+				NewPredicate("c.tags", OperatorOverlaps, []interface{}{"a", "b"}),
+			}, {
+				NewPredicate("c.tags", OperatorEqual, []interface{}{"a"}).WithQuantifier(QuantifierAny),
+				NewPredicate("c.scores", OperatorLessThan, []interface{}{10}).WithQuantifier(QuantifierAll),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: []string{
+				"( c.id = ANY($0) )",
+				"( c.tags @> $1 OR c.tags <@ $2 OR c.tags && $3 )",
+				"( $4 = ANY(c.tags) OR $5 > ALL(c.scores) )",
+			},
+			err: nil,
+		},
+	}, {
+		message: "in operator with array and funcs",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("c.name", OperatorIn, []interface{}{"a", "b"}, "lower").AsArray(),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{errOperatorInArray},
+		},
+	}, {
+		message: "quantifier with like operator",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("c.tags", OperatorLike, []interface{}{"a%"}).WithQuantifier(QuantifierAny),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{errQuantifier},
+		},
+	}, {
+		message: "jsonb operators and paths",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("c.phones", OperatorJSONContains, []interface{}{[]string{"01.02.03.04.05"}}),
+			}, {
+				NewPredicate("c.data", OperatorJSONHasKey, []interface{}{"address"}),
+				NewPredicate("c.data", OperatorJSONHasAnyKeys, []interface{}{"a", "b"}),
+				NewPredicate("c.data", OperatorJSONHasAllKeys, []interface{}{"a", "b"}),
+			}, {
+				NewPredicate("c.data", OperatorJSONPathExists, []interface{}{"$.tags[*] ? (@ == \"a\")"}),
+				NewPredicate("c.data", OperatorJSONPathMatch, []interface{}{"$.count > 2"}),
+			}, {
+				NewPredicate(JSONPath("c.data", "address", "city"), OperatorEqual, []interface{}{"Paris"}, "lower"),
+				NewPredicate("c.data->'items'->0->>'name'", OperatorLike, []interface{}{"a%"}),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: []string{
+				"( c.phones @> $0::jsonb )",
+				"( c.data ? $1 OR c.data ?| $2::text[] OR c.data ?& $3::text[] )",
+				"( c.data @? $4::jsonpath OR c.data @@ $5::jsonpath )",
+				"( lower(c.data->'address'->>'city') = lower($6) OR c.data->'items'->0->>'name' LIKE $7 )",
+			},
+			err: nil,
+		},
+	}, {
+		message: "invalid json path segment",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("c.data->>'a'' or 1=1 --'", OperatorEqual, []interface{}{"x"}),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{fmt.Errorf("%w, got %q", errInvalidJSONPath, "'a'' or 1=1 --'")},
+		},
+	}, {
+		message: "json has key operator with non string value",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("c.data", OperatorJSONHasKey, []interface{}{1}),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{errOperatorJSONHasKey},
+		},
+	}, {
+		message: "funcs with args and sides",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("c.created_at", OperatorEqual, []interface{}{"2020-01-01"}).WithFuncs(NewFunc("date_trunc", FuncSideField, "day").WithOperandAt(1)),
+			}, {
+				NewPredicate("c.score", OperatorGreaterThan, []interface{}{10}).WithFuncs(NewFunc("coalesce", FuncSideField, 0)),
+			}, {
+				NewPredicate("c.name", OperatorLike, []interface{}{"ACME%"}).WithFuncs(NewFunc("lower", FuncSideValue)),
+			}, {
+				NewPredicate("c.name", OperatorIn, []interface{}{"a", "b"}, "unaccent").WithFuncs(NewFunc("lower", FuncSideBoth)),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: []string{
+				"( date_trunc($0, c.created_at) = $1 )",
+				"( coalesce(c.score, $2) > $3 )",
+				"( c.name LIKE lower($4) )",
+				"( lower(unaccent(c.name)) IN (lower(unaccent($5)), lower(unaccent($6))) )",
+			},
+			err: nil,
+		},
+	}, {
+		message: "func not allowed",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("c.id", OperatorEqual, []interface{}{1}, "pg_sleep"),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{fmt.Errorf("%w, got %q", errExpressionFunc, "pg_sleep")},
+		},
+	}, {
+		message: "func with invalid operand position",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("c.id", OperatorEqual, []interface{}{1}).WithFuncs(NewFunc("coalesce", FuncSideField, 0).WithOperandAt(2)),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{fmt.Errorf("%w, got %d", errFuncOperandPosition, 2)},
+		},
+	}, {
+		message: "matching modes",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("c.name", OperatorLike, []interface{}{"acme%"}).WithMatching(MatchingCaseInsensitive),
+			}, {
+				NewPredicate("c.name", OperatorLike, []interface{}{"acme%"}).WithMatching(MatchingInsensitive),
+			}, {
+				NewPredicate("c.name", OperatorEqual, []interface{}{"Acme"}).WithMatching(MatchingInsensitive),
+			}, {
+				NewPredicate("c.city", OperatorIn, []interface{}{"Orléans", "Nîmes"}).WithMatching(MatchingAccentInsensitive),
+			}, {
+				NewPredicate("c.email", OperatorNotEqual, []interface{}{"a@b.c"}),
+				NewPredicate("c.email", OperatorIsNull, nil),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: []string{
+				"( c.name ILIKE $0 )",
+				"( unaccent(c.name) ILIKE unaccent($1) )",
+				"( lower(unaccent(c.name)) = lower(unaccent($2)) )",
+				"( unaccent(c.city) IN (unaccent($3), unaccent($4)) )",
+				"( lower(c.email) <> lower($5) OR c.email IS NULL )",
+			},
+			err: nil,
+		},
+	}, {
+		message: "matching with unsupported operator",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("c.ids", OperatorIn, []interface{}{"a"}).AsArray().WithMatching(MatchingCaseInsensitive),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{fmt.Errorf("%w, got %q", errMatching, OperatorIn)},
+		},
+	}, {
+		message: "invalid matching",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("c.name", OperatorEqual, []interface{}{"a"}).WithMatching("LOWER"),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{fmt.Errorf("%w, got %q", errMatching, "LOWER")},
+		},
+	}, {
+		message: "time range and date part",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewTimeRangePredicate("c.created_at", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+			}, {
+				datePart,
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: []string{
+				"( c.created_at >= $0 AND c.created_at < $1 )",
+				"( date_part($3, timezone($2, c.created_at)) = $4 )",
+			},
+			err: nil,
+		},
+	}, {
+		message: "invalid values length with time range operator",
+		in: &in{
+			predicates: [][]*Predicate{{
+				NewPredicate("c.created_at", OperatorTimeRange, []interface{}{time.Now()}),
+			}},
+			cond: &sqlbuilder.PostgreSQL.NewSelectBuilder().Cond,
+		},
+		out: &out{
+			res: nil,
+			err: &QueryError{errOperatorTimeRange},
+		},
 	}, {
 		message: "equal operator without funcs",
 		in: &in{
@@ -157,8 +539,11 @@ func TestPredicatesToString(t *testing.T) {
 		},
 	}}
 
+	cfg := newBuildConfig()
+	cfg.matchings["c.email"] = MatchingCaseInsensitive
+
 	for _, test := range tests {
-		res, err := predicatesToStrings(test.in.predicates, test.in.cond)
+		res, err := predicatesToStrings(test.in.predicates, test.in.cond, cfg)
 
 		if test.out.err != nil {
 			var qe *QueryError
@@ -176,3 +561,72 @@ func TestPredicatesToString(t *testing.T) {
 		}
 	}
 }
+
+func TestOperatorUnmarshalJSON(t *testing.T) {
+	type in struct {
+		json string
+	}
+
+	type out struct {
+		res Operator
+		err error
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var tests = []*test{{
+		message: "operator",
+		in:      &in{json: `"GREATER THAN"`},
+		out:     &out{res: OperatorGreaterThan},
+	}, {
+		message: "lowercase operator",
+		in:      &in{json: `"is null"`},
+		out:     &out{res: OperatorIsNull},
+	}, {
+		message: "alias",
+		in:      &in{json: `"gt"`},
+		out:     &out{res: OperatorGreaterThan},
+	}, {
+		message: "symbol alias",
+		in:      &in{json: `">="`},
+		out:     &out{res: OperatorGreaterThanOrEqual},
+	}, {
+		message: "uppercase alias",
+		in:      &in{json: `"EQ"`},
+		out:     &out{res: OperatorEqual},
+	}, {
+		message: "unknown operator",
+		in:      &in{json: `"GREATER"`},
+		out:     &out{err: fmt.Errorf("%w, got %q", errUnknownOperator, "GREATER")},
+	}, {
+		message: "server operator",
+		in:      &in{json: `"exists"`},
+		out:     &out{err: fmt.Errorf("%w, got %q", errUnknownOperator, "exists")},
+	}, {
+		message: "raw operator",
+		in:      &in{json: `"RAW"`},
+		out:     &out{err: fmt.Errorf("%w, got %q", errUnknownOperator, "RAW")},
+	}, {
+		message: "not a string",
+		in:      &in{json: `1`},
+		out:     &out{err: fmt.Errorf("%w, got %s", errUnknownOperator, "1")},
+	}}
+
+	for _, test := range tests {
+		var p Predicate
+		err := json.Unmarshal([]byte(`{"operator":`+test.in.json+`}`), &p)
+
+		if test.out.err != nil {
+			assert.True(t, errors.Is(err, errUnknownOperator), test.message)
+			assert.Equal(t, test.out.err.Error(), err.Error(), test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+		}
+
+		assert.Equal(t, test.out.res, p.Operator, test.message)
+	}
+}
@@ -0,0 +1,265 @@
+package visisql
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var errQueryStringParam = errors.New("query string parameter must be a field optionally followed by an operator in brackets")
+var errQueryStringField = errors.New("query string field is not allowed")
+var errQueryStringOperator = errors.New("query string operator is not allowed for this field")
+var errQueryStringValues = errors.New("query string values count does not match the operator")
+var errQueryStringValue = errors.New("query string value is invalid")
+var errQueryStringSort = errors.New("query string sort field is not allowed")
+var errQueryStringPagination = errors.New("query string start and limit must be non negative integers")
+
+const (
+	QueryStringSort  = "sort"
+	QueryStringStart = "start"
+	QueryStringLimit = "limit"
+)
+
+var queryStringParamRegexp = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_.]*)(?:\[([a-z]+)\])?$`)
+
+var queryStringOperators = map[string]Operator{
+	"eq":      OperatorEqual,
+	"ne":      OperatorNotEqual,
+	"lt":      OperatorLessThan,
+	"lte":     OperatorLessThanOrEqual,
+	"gt":      OperatorGreaterThan,
+	"gte":     OperatorGreaterThanOrEqual,
+	"like":    OperatorLike,
+	"in":      OperatorIn,
+	"between": OperatorBetween,
+	"null":    OperatorIsNull,
+}
+
+type QueryStringField struct {
+	Name      string
+	Column    string
+	Operators []Operator
+	Sortable  bool
+	Type      ValueType
+	Parse     func(value string) (interface{}, error)
+}
+
+func NewQueryStringField(name string, column string, operators ...Operator) *QueryStringField {
+	return &QueryStringField{Name: name, Column: column, Operators: operators}
+}
+
+func (f *QueryStringField) WithSort() *QueryStringField {
+	f.Sortable = true
+	return f
+}
+
+func (f *QueryStringField) WithType(t ValueType) *QueryStringField {
+	f.Type = t
+	return f
+}
+
+func (f *QueryStringField) WithParser(parse func(value string) (interface{}, error)) *QueryStringField {
+	f.Parse = parse
+	return f
+}
+
+func (f *QueryStringField) allows(operator Operator) bool {
+	if len(f.Operators) == 0 {
+		return operator == OperatorEqual
+	}
+
+	for _, o := range f.Operators {
+		if o == operator {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *QueryStringField) column() string {
+	if f.Column == "" {
+		return f.Name
+	}
+
+	return f.Column
+}
+
+func (f *QueryStringField) values(operator Operator, raw string) ([]interface{}, error) {
+	var parts []string
+	switch operator {
+	case OperatorIsNull:
+		if raw != "" && raw != "true" {
+			return nil, errQueryStringValues
+		}
+
+		return nil, nil
+	case OperatorIn, OperatorBetween:
+		parts = strings.Split(raw, ",")
+	default:
+		parts = []string{raw}
+	}
+
+	if operator == OperatorBetween && len(parts) != 2 {
+		return nil, errQueryStringValues
+	}
+
+	values := make([]interface{}, 0, len(parts))
+	for _, p := range parts {
+		if f.Parse == nil {
+			values = append(values, p)
+			continue
+		}
+
+		v, err := f.Parse(p)
+		if err != nil {
+			return nil, fmt.Errorf("%w, got %q: %s", errQueryStringValue, p, err)
+		}
+
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+type QueryStringParser struct {
+	fields map[string]*QueryStringField
+}
+
+func NewQueryStringParser(fields ...*QueryStringField) *QueryStringParser {
+	m := make(map[string]*QueryStringField, len(fields))
+	for _, f := range fields {
+		m[f.Name] = f
+	}
+
+	return &QueryStringParser{fields: m}
+}
+
+// Parse turns query values like name[like]=Foo%, id[in]=1,2, sort=-created_at,name, start=0 and limit=20
+// into predicates, orders and pagination. Every parameter other than sort, start and limit must be an allowed field.
+func (p *QueryStringParser) Parse(values url.Values) ([][]*Predicate, []*OrderBy, *Pagination, error) {
+	params := make([]string, 0, len(values))
+	for k := range values {
+		params = append(params, k)
+	}
+
+	sort.Strings(params)
+
+	var predicates [][]*Predicate
+	for _, param := range params {
+		switch param {
+		case QueryStringSort, QueryStringStart, QueryStringLimit:
+			continue
+		}
+
+		for _, raw := range values[param] {
+			predicate, err := p.predicate(param, raw)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("visisql query string: %w", &ParseError{err})
+			}
+
+			predicates = append(predicates, []*Predicate{predicate})
+		}
+	}
+
+	orders, err := p.orders(values[QueryStringSort])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("visisql query string: %w", &ParseError{err})
+	}
+
+	pagination, err := p.pagination(values)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("visisql query string: %w", &ParseError{err})
+	}
+
+	return predicates, orders, pagination, nil
+}
+
+func (p *QueryStringParser) predicate(param string, raw string) (*Predicate, error) {
+	m := queryStringParamRegexp.FindStringSubmatch(param)
+	if m == nil {
+		return nil, fmt.Errorf("%w, got %q", errQueryStringParam, param)
+	}
+
+	f, ok := p.fields[m[1]]
+	if !ok {
+		return nil, fmt.Errorf("%w, got %q", errQueryStringField, m[1])
+	}
+
+	operator := OperatorEqual
+	if m[2] != "" {
+		if operator, ok = queryStringOperators[m[2]]; !ok {
+			return nil, fmt.Errorf("%w, got %q", errQueryStringOperator, param)
+		}
+	}
+
+	if !f.allows(operator) {
+		return nil, fmt.Errorf("%w, got %q", errQueryStringOperator, param)
+	}
+
+	values, err := f.values(operator, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", param, err)
+	}
+
+	return NewPredicate(f.column(), operator, values), nil
+}
+
+func (p *QueryStringParser) orders(sorts []string) ([]*OrderBy, error) {
+	var orders []*OrderBy
+	for _, s := range sorts {
+		for _, name := range strings.Split(s, ",") {
+			order := OrderAsc
+			if strings.HasPrefix(name, "-") {
+				order = OrderDesc
+			}
+
+			name = strings.TrimLeft(name, "+-")
+			f, ok := p.fields[name]
+			if !ok || !f.Sortable {
+				return nil, fmt.Errorf("%w, got %q", errQueryStringSort, name)
+			}
+
+			orders = append(orders, NewOrderBy(f.column(), order))
+		}
+	}
+
+	return orders, nil
+}
+
+func (p *QueryStringParser) pagination(values url.Values) (*Pagination, error) {
+	if _, ok := values[QueryStringStart]; !ok {
+		if _, ok := values[QueryStringLimit]; !ok {
+			return nil, nil
+		}
+	}
+
+	start, err := queryStringInt(values.Get(QueryStringStart))
+	if err != nil {
+		return nil, fmt.Errorf("%w, got %q", errQueryStringPagination, values.Get(QueryStringStart))
+	}
+
+	limit, err := queryStringInt(values.Get(QueryStringLimit))
+	if err != nil {
+		return nil, fmt.Errorf("%w, got %q", errQueryStringPagination, values.Get(QueryStringLimit))
+	}
+
+	return NewPagination(start, limit), nil
+}
+
+func queryStringInt(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 0 {
+		return 0, errQueryStringPagination
+	}
+
+	return i, nil
+}
@@ -0,0 +1,114 @@
+package visisql
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryStringParse(t *testing.T) {
+	type in struct {
+		query string
+	}
+
+	type out struct {
+		predicates [][]*Predicate
+		orders     []*OrderBy
+		pagination *Pagination
+		err        error
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var parser = NewQueryStringParser(
+		NewQueryStringField("id", "c.id", OperatorEqual, OperatorIn, OperatorBetween).WithParser(func(v string) (interface{}, error) {
+			return strconv.Atoi(v)
+		}),
+		NewQueryStringField("name", "c.name", OperatorEqual, OperatorLike, OperatorIsNull).WithSort(),
+		NewQueryStringField("created_at", "c.created_at", OperatorGreaterThan).WithSort(),
+		NewQueryStringField("status", ""),
+	)
+
+	var tests = []*test{{
+		message: "filters, sort and pagination",
+		in:      &in{query: "name[like]=Foo%25&id[in]=1,2&status=active&sort=-created_at,name&start=0&limit=20"},
+		out: &out{
+			predicates: [][]*Predicate{
+				{NewPredicate("c.id", OperatorIn, []interface{}{1, 2})},
+				{NewPredicate("c.name", OperatorLike, []interface{}{"Foo%"})},
+				{NewPredicate("status", OperatorEqual, []interface{}{"active"})},
+			},
+			orders:     []*OrderBy{NewOrderBy("c.created_at", OrderDesc), NewOrderBy("c.name", OrderAsc)},
+			pagination: NewPagination(0, 20),
+		},
+	}, {
+		message: "between and is null",
+		in:      &in{query: "id[between]=1,10&name[null]"},
+		out: &out{
+			predicates: [][]*Predicate{
+				{NewPredicate("c.id", OperatorBetween, []interface{}{1, 10})},
+				{NewPredicate("c.name", OperatorIsNull, nil)},
+			},
+		},
+	}, {
+		message: "unknown field",
+		in:      &in{query: "password=secret"},
+		out:     &out{err: &ParseError{fmt.Errorf("%w, got %q", errQueryStringField, "password")}},
+	}, {
+		message: "invalid parameter",
+		in:      &in{query: "name[like=a"},
+		out:     &out{err: &ParseError{fmt.Errorf("%w, got %q", errQueryStringParam, "name[like")}},
+	}, {
+		message: "operator not allowed",
+		in:      &in{query: "status[like]=a%25"},
+		out:     &out{err: &ParseError{fmt.Errorf("%w, got %q", errQueryStringOperator, "status[like]")}},
+	}, {
+		message: "unknown operator",
+		in:      &in{query: "id[regex]=1"},
+		out:     &out{err: &ParseError{fmt.Errorf("%w, got %q", errQueryStringOperator, "id[regex]")}},
+	}, {
+		message: "invalid between values",
+		in:      &in{query: "id[between]=1"},
+		out:     &out{err: &ParseError{fmt.Errorf("%s: %w", "id[between]", errQueryStringValues)}},
+	}, {
+		message: "invalid value",
+		in:      &in{query: "id=abc"},
+		out:     &out{err: &ParseError{fmt.Errorf("%s: %w", "id", fmt.Errorf("%w, got %q: %s", errQueryStringValue, "abc", `strconv.Atoi: parsing "abc": invalid syntax`))}},
+	}, {
+		message: "sort field not sortable",
+		in:      &in{query: "sort=-id"},
+		out:     &out{err: &ParseError{fmt.Errorf("%w, got %q", errQueryStringSort, "id")}},
+	}, {
+		message: "negative limit",
+		in:      &in{query: "limit=-1"},
+		out:     &out{err: &ParseError{fmt.Errorf("%w, got %q", errQueryStringPagination, "-1")}},
+	}}
+
+	for _, test := range tests {
+		values, err := url.ParseQuery(test.in.query)
+		assert.Nil(t, err, test.message)
+
+		predicates, orders, pagination, err := parser.Parse(values)
+
+		if test.out.err != nil {
+			var pe *ParseError
+
+			assert.True(t, errors.As(err, &pe), test.message)
+			assert.Equal(t, test.out.err.Error(), pe.Error(), test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+		}
+
+		assert.Equal(t, test.out.predicates, predicates, test.message)
+		assert.Equal(t, test.out.orders, orders, test.message)
+		assert.Equal(t, test.out.pagination, pagination, test.message)
+	}
+}
@@ -0,0 +1,73 @@
+package visisql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var errRangeBounds = errors.New("range bounds must be one of [], (), [) or (]")
+var errOperatorRange = errors.New("predicate must have only one range value when operator is a range operator")
+
+type RangeBounds string
+
+const (
+	RangeBoundsInclusive          RangeBounds = "[]"
+	RangeBoundsExclusive          RangeBounds = "()"
+	RangeBoundsInclusiveExclusive RangeBounds = "[)"
+	RangeBoundsExclusiveInclusive RangeBounds = "(]"
+)
+
+type Range struct {
+	Lower  interface{} `json:"lower"`
+	Upper  interface{} `json:"upper"`
+	Bounds RangeBounds `json:"bounds"`
+}
+
+func NewRange(lower, upper interface{}, bounds RangeBounds) *Range {
+	return &Range{Lower: lower, Upper: upper, Bounds: bounds}
+}
+
+func (r Range) Value() (driver.Value, error) {
+	switch r.Bounds {
+	case RangeBoundsInclusive, RangeBoundsExclusive, RangeBoundsInclusiveExclusive, RangeBoundsExclusiveInclusive:
+	default:
+		return nil, fmt.Errorf("%w, got %q", errRangeBounds, r.Bounds)
+	}
+
+	return fmt.Sprintf("%c%s,%s%c", r.Bounds[0], rangeElement(r.Lower), rangeElement(r.Upper), r.Bounds[1]), nil
+}
+
+func rangeElement(v interface{}) string {
+	var s string
+	switch e := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		s = e.Format("2006-01-02 15:04:05.999999999Z07:00")
+	case *time.Time:
+		if e == nil {
+			return ""
+		}
+		s = e.Format("2006-01-02 15:04:05.999999999Z07:00")
+	default:
+		s = fmt.Sprint(e)
+	}
+
+	return fmt.Sprintf(`"%s"`, strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s))
+}
+
+func rangeValue(v interface{}) (driver.Valuer, error) {
+	switch r := v.(type) {
+	case Range:
+		return r, nil
+	case *Range:
+		if r != nil {
+			return r, nil
+		}
+	}
+
+	return nil, errOperatorRange
+}
@@ -0,0 +1,62 @@
+package visisql
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangeValue(t *testing.T) {
+	type in struct {
+		r *Range
+	}
+
+	type out struct {
+		res interface{}
+		err error
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var start = time.Date(2024, 1, 1, 8, 30, 0, 0, time.UTC)
+	var end = time.Date(2024, 1, 1, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	var tests = []*test{{
+		message: "time range inclusive exclusive",
+		in:      &in{r: NewRange(start, end, RangeBoundsInclusiveExclusive)},
+		out:     &out{res: `["2024-01-01 08:30:00Z","2024-01-01 10:00:00+01:00")`},
+	}, {
+		message: "integer range exclusive inclusive",
+		in:      &in{r: NewRange(1, 10, RangeBoundsExclusiveInclusive)},
+		out:     &out{res: `("1","10"]`},
+	}, {
+		message: "unbounded upper",
+		in:      &in{r: NewRange(&start, nil, RangeBoundsInclusive)},
+		out:     &out{res: `["2024-01-01 08:30:00Z",]`},
+	}, {
+		message: "escaped element",
+		in:      &in{r: NewRange(`a"b`, `c\d`, RangeBoundsExclusive)},
+		out:     &out{res: `("a\"b","c\\d")`},
+	}, {
+		message: "invalid bounds",
+		in:      &in{r: NewRange(1, 2, "[[")},
+		out:     &out{err: errRangeBounds},
+	}}
+
+	for _, test := range tests {
+		res, err := test.in.r.Value()
+
+		if test.out.err != nil {
+			assert.True(t, errors.Is(err, test.out.err), test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+			assert.Equal(t, test.out.res, res, test.message)
+		}
+	}
+}
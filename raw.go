@@ -0,0 +1,102 @@
+package visisql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/huandu/go-sqlbuilder"
+)
+
+var errRawEmpty = errors.New("raw predicate must have an expression")
+var errRawArgs = errors.New("raw predicate must have as many values as ? placeholders")
+var errRawNamedArg = errors.New("raw predicate named placeholder has no value")
+
+// Raw returns a predicate written in the query as is, whose ? placeholders are bound to args.
+// A literal ? (e.g. jsonb operator) is written ??, except inside strings and quoted identifiers. The expression is NOT SAFE for client input,
+// it must never be built from request values, which must be passed as args instead.
+func Raw(expr string, args ...interface{}) *Predicate {
+	return &Predicate{Operator: OperatorRaw, Raw: expr, Values: args}
+}
+
+// RawNamed is like Raw, but binds :name placeholders to named args.
+// The expression is NOT SAFE for client input.
+func RawNamed(expr string, named map[string]interface{}) *Predicate {
+	return &Predicate{Operator: OperatorRaw, Raw: expr, Named: named}
+}
+
+func (p *Predicate) rawToString(cond *sqlbuilder.Cond) (string, error) {
+	if strings.TrimSpace(p.Raw) == "" {
+		return "", errRawEmpty
+	}
+
+	var b strings.Builder
+	named := make(map[string]string)
+
+	s := p.Raw
+	n := 0
+	start := 0
+	flush := func(i int) {
+		b.WriteString(sqlbuilder.Escape(s[start:i]))
+	}
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\'':
+			// E'...' strings escape quotes with a backslash
+			escaped := i > 0 && (s[i-1] == 'E' || s[i-1] == 'e') && (i == 1 || !isIdentChar(s[i-2]))
+			for i++; i < len(s) && s[i] != '\''; i++ {
+				if escaped && s[i] == '\\' {
+					i++
+				}
+			}
+		case c == '"':
+			for i++; i < len(s) && s[i] != '"'; i++ {
+			}
+		case c == '?' && i+1 < len(s) && s[i+1] == '?':
+			flush(i)
+			b.WriteByte('?')
+			i++
+			start = i + 1
+		case c == '?':
+			if n >= len(p.Values) {
+				return "", errRawArgs
+			}
+
+			flush(i)
+			b.WriteString(p.arg(cond, p.Values[n]))
+			n++
+			start = i + 1
+		case c == ':' && i+1 < len(s) && s[i+1] == ':':
+			i++
+		case c == ':' && i+1 < len(s) && isIdentStart(s[i+1]) && p.Named != nil:
+			j := i + 1
+			for j < len(s) && (isIdentStart(s[j]) || s[j] >= '0' && s[j] <= '9') {
+				j++
+			}
+
+			name := s[i+1 : j]
+			v, ok := p.Named[name]
+			if !ok {
+				return "", fmt.Errorf("%w, got %q", errRawNamedArg, name)
+			}
+
+			if _, ok := named[name]; !ok {
+				named[name] = p.arg(cond, v)
+			}
+
+			flush(i)
+			b.WriteString(named[name])
+			i = j - 1
+			start = j
+		}
+	}
+
+	if n != len(p.Values) {
+		return "", errRawArgs
+	}
+
+	flush(len(s))
+
+	return fmt.Sprintf("(%s)", b.String()), nil
+}
@@ -0,0 +1,192 @@
+package visisql
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ValueType string
+
+const (
+	ValueTypeString   ValueType = "string"
+	ValueTypeInteger  ValueType = "integer"
+	ValueTypeNumber   ValueType = "number"
+	ValueTypeBoolean  ValueType = "boolean"
+	ValueTypeDate     ValueType = "date"
+	ValueTypeDateTime ValueType = "date-time"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+func (t ValueType) schema() map[string]interface{} {
+	switch t {
+	case "":
+		return map[string]interface{}{}
+	case ValueTypeDate, ValueTypeDateTime:
+		return map[string]interface{}{"type": "string", "format": string(t)}
+	}
+
+	return map[string]interface{}{"type": string(t)}
+}
+
+// JSONSchema describes the JSON payload of predicates, orders and pagination allowed for fields,
+// with the values count rules used to build queries.
+func JSONSchema(fields ...*QueryStringField) map[string]interface{} {
+	defs := filterSchemas(fields, "#/$defs/")
+
+	schema := defs["Filter"].(map[string]interface{})
+	delete(defs, "Filter")
+
+	schema["$schema"] = jsonSchemaDraft
+	schema["$defs"] = defs
+
+	return schema
+}
+
+// OpenAPIComponents describes the same payload as JSONSchema as OpenAPI 3.1 components.
+func OpenAPIComponents(fields ...*QueryStringField) map[string]interface{} {
+	return map[string]interface{}{"schemas": filterSchemas(fields, "#/components/schemas/")}
+}
+
+func filterSchemas(fields []*QueryStringField, refPrefix string) map[string]interface{} {
+	ref := func(name string) map[string]interface{} {
+		return map[string]interface{}{"$ref": refPrefix + name}
+	}
+
+	var predicates []interface{}
+	var sortable []interface{}
+	seen := make(map[string]bool)
+	for _, f := range fields {
+		operators := f.Operators
+		if len(operators) == 0 {
+			operators = []Operator{OperatorEqual}
+		}
+
+		for _, o := range operators {
+			// oneOf fails when several branches match, so a column and operator is only described once
+			key := fmt.Sprintf("%s %s", f.column(), o)
+			if seen[key] {
+				continue
+			}
+
+			if s := f.predicateSchema(o); s != nil {
+				seen[key] = true
+				predicates = append(predicates, s)
+			}
+		}
+
+		if f.Sortable {
+			sortable = append(sortable, f.column())
+		}
+	}
+
+	properties := map[string]interface{}{
+		"predicates": map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "array", "items": ref("Predicate")},
+		},
+		"pagination": ref("Pagination"),
+	}
+
+	schemas := map[string]interface{}{
+		"Filter": map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		},
+		"Pagination": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"start":    map[string]interface{}{"type": "integer", "minimum": 0},
+				"limit":    map[string]interface{}{"type": "integer", "minimum": 0},
+				"page":     map[string]interface{}{"type": "integer", "minimum": 1},
+				"pageSize": map[string]interface{}{"type": "integer", "minimum": 1},
+			},
+			"additionalProperties": false,
+		},
+	}
+
+	// an empty oneOf is invalid, false rejects every predicate
+	if len(predicates) > 0 {
+		schemas["Predicate"] = map[string]interface{}{"oneOf": predicates}
+	} else {
+		schemas["Predicate"] = false
+	}
+
+	if len(sortable) > 0 {
+		properties["orderBy"] = map[string]interface{}{"type": "array", "items": ref("OrderBy")}
+		schemas["OrderBy"] = map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"field": map[string]interface{}{"enum": sortable},
+				"order": keywordSchema(string(OrderAsc), string(OrderDesc)),
+				"nulls": keywordSchema(string(NullsFirst), string(NullsLast)),
+			},
+			"required":             []interface{}{"field"},
+			"additionalProperties": false,
+		}
+	}
+
+	return schemas
+}
+
+func (f *QueryStringField) predicateSchema(operator Operator) map[string]interface{} {
+	if serverOperators[operator] {
+		return nil
+	}
+
+	values := map[string]interface{}{"type": []interface{}{"array", "null"}, "items": f.Type.schema()}
+	required := []interface{}{"field", "operator"}
+
+	if r, ok := operatorValuesRules[operator]; ok {
+		values["minItems"] = r.min
+		if r.max >= 0 {
+			values["maxItems"] = r.max
+		}
+
+		if r.strings {
+			values["items"] = ValueTypeString.schema()
+		}
+
+		if r.min > 0 {
+			values["type"] = "array"
+			required = append(required, "values")
+		}
+	}
+
+	properties := map[string]interface{}{
+		"field":    map[string]interface{}{"const": f.column()},
+		"operator": map[string]interface{}{"const": operator},
+		"values":   values,
+	}
+
+	if (&Predicate{Operator: operator}).matchable() {
+		properties["matching"] = map[string]interface{}{"enum": []interface{}{MatchingCaseInsensitive, MatchingAccentInsensitive, MatchingInsensitive}}
+	}
+
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// keywordSchema matches keywords like their text decoding, case insensitively, trimmed and
+// optionally empty or null for the default.
+func keywordSchema(keywords ...string) map[string]interface{} {
+	alternatives := make([]string, 0, len(keywords))
+	for _, k := range keywords {
+		var b strings.Builder
+		for _, r := range k {
+			b.WriteString(fmt.Sprintf("[%s%s]", strings.ToUpper(string(r)), strings.ToLower(string(r))))
+		}
+
+		alternatives = append(alternatives, b.String())
+	}
+
+	return map[string]interface{}{
+		"type":    []interface{}{"string", "null"},
+		"pattern": fmt.Sprintf(`^\s*(%s)?\s*$`, strings.Join(alternatives, "|")),
+	}
+}
@@ -0,0 +1,197 @@
+package visisql
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONSchema(t *testing.T) {
+	type in struct {
+		fields []*QueryStringField
+	}
+
+	type out struct {
+		res string
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var tests = []*test{{
+		message: "values rules and types",
+		in: &in{fields: []*QueryStringField{
+			NewQueryStringField("created_at", "c.created_at", OperatorBetween).WithType(ValueTypeDateTime),
+			NewQueryStringField("data", "c.data", OperatorJSONHasAnyKeys, OperatorRaw),
+		}},
+		out: &out{res: `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {
+				"predicates": {"type": "array", "items": {"type": "array", "items": {"$ref": "#/$defs/Predicate"}}},
+				"pagination": {"$ref": "#/$defs/Pagination"}
+			},
+			"additionalProperties": false,
+			"$defs": {
+				"Predicate": {"oneOf": [{
+					"type": "object",
+					"properties": {
+						"field": {"const": "c.created_at"},
+						"operator": {"const": "BETWEEN"},
+						"values": {"type": "array", "items": {"type": "string", "format": "date-time"}, "minItems": 2, "maxItems": 2}
+					},
+					"required": ["field", "operator", "values"],
+					"additionalProperties": false
+				}, {
+					"type": "object",
+					"properties": {
+						"field": {"const": "c.data"},
+						"operator": {"const": "JSON HAS ANY KEYS"},
+						"values": {"type": "array", "items": {"type": "string"}, "minItems": 1}
+					},
+					"required": ["field", "operator", "values"],
+					"additionalProperties": false
+				}]},
+				"Pagination": {
+					"type": "object",
+					"properties": {
+						"start": {"type": "integer", "minimum": 0},
+						"limit": {"type": "integer", "minimum": 0},
+						"page": {"type": "integer", "minimum": 1},
+						"pageSize": {"type": "integer", "minimum": 1}
+					},
+					"additionalProperties": false
+				}
+			}
+		}`},
+	}, {
+		message: "default operator, matching and order by",
+		in: &in{fields: []*QueryStringField{
+			NewQueryStringField("status", "").WithSort(),
+		}},
+		out: &out{res: `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {
+				"predicates": {"type": "array", "items": {"type": "array", "items": {"$ref": "#/$defs/Predicate"}}},
+				"orderBy": {"type": "array", "items": {"$ref": "#/$defs/OrderBy"}},
+				"pagination": {"$ref": "#/$defs/Pagination"}
+			},
+			"additionalProperties": false,
+			"$defs": {
+				"Predicate": {"oneOf": [{
+					"type": "object",
+					"properties": {
+						"field": {"const": "status"},
+						"operator": {"const": "EQUALS"},
+						"values": {"type": "array", "items": {}, "minItems": 1, "maxItems": 1},
+						"matching": {"enum": ["CASE INSENSITIVE", "ACCENT INSENSITIVE", "INSENSITIVE"]}
+					},
+					"required": ["field", "operator", "values"],
+					"additionalProperties": false
+				}]},
+				"OrderBy": {
+					"type": "object",
+					"properties": {
+						"field": {"enum": ["status"]},
+						"order": {"type": ["string", "null"], "pattern": "^\\s*([Aa][Ss][Cc]|[Dd][Ee][Ss][Cc])?\\s*$"},
+						"nulls": {"type": ["string", "null"], "pattern": "^\\s*([Ff][Ii][Rr][Ss][Tt]|[Ll][Aa][Ss][Tt])?\\s*$"}
+					},
+					"required": ["field"],
+					"additionalProperties": false
+				},
+				"Pagination": {
+					"type": "object",
+					"properties": {
+						"start": {"type": "integer", "minimum": 0},
+						"limit": {"type": "integer", "minimum": 0},
+						"page": {"type": "integer", "minimum": 1},
+						"pageSize": {"type": "integer", "minimum": 1}
+					},
+					"additionalProperties": false
+				}
+			}
+		}`},
+	}, {
+		message: "duplicate column and operator",
+		in: &in{fields: []*QueryStringField{
+			NewQueryStringField("name", "c.name", OperatorIsNull),
+			NewQueryStringField("title", "c.name", OperatorIsNull),
+		}},
+		out: &out{res: `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {
+				"predicates": {"type": "array", "items": {"type": "array", "items": {"$ref": "#/$defs/Predicate"}}},
+				"pagination": {"$ref": "#/$defs/Pagination"}
+			},
+			"additionalProperties": false,
+			"$defs": {
+				"Predicate": {"oneOf": [{
+					"type": "object",
+					"properties": {
+						"field": {"const": "c.name"},
+						"operator": {"const": "IS NULL"},
+						"values": {"type": ["array", "null"], "items": {}, "minItems": 0, "maxItems": 0}
+					},
+					"required": ["field", "operator"],
+					"additionalProperties": false
+				}]},
+				"Pagination": {
+					"type": "object",
+					"properties": {
+						"start": {"type": "integer", "minimum": 0},
+						"limit": {"type": "integer", "minimum": 0},
+						"page": {"type": "integer", "minimum": 1},
+						"pageSize": {"type": "integer", "minimum": 1}
+					},
+					"additionalProperties": false
+				}
+			}
+		}`},
+	}, {
+		message: "no predicate allowed",
+		in:      &in{fields: []*QueryStringField{NewQueryStringField("data", "c.data", OperatorRaw)}},
+		out: &out{res: `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {
+				"predicates": {"type": "array", "items": {"type": "array", "items": {"$ref": "#/$defs/Predicate"}}},
+				"pagination": {"$ref": "#/$defs/Pagination"}
+			},
+			"additionalProperties": false,
+			"$defs": {
+				"Predicate": false,
+				"Pagination": {
+					"type": "object",
+					"properties": {
+						"start": {"type": "integer", "minimum": 0},
+						"limit": {"type": "integer", "minimum": 0},
+						"page": {"type": "integer", "minimum": 1},
+						"pageSize": {"type": "integer", "minimum": 1}
+					},
+					"additionalProperties": false
+				}
+			}
+		}`},
+	}}
+
+	for _, test := range tests {
+		b, err := json.Marshal(JSONSchema(test.in.fields...))
+
+		assert.Nil(t, err, test.message)
+		assert.JSONEq(t, test.out.res, string(b), test.message)
+	}
+}
+
+func TestOpenAPIComponents(t *testing.T) {
+	b, err := json.Marshal(OpenAPIComponents(NewQueryStringField("status", "")))
+
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), `"Filter":{`)
+	assert.Contains(t, string(b), `"$ref":"#/components/schemas/Predicate"`)
+}
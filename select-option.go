@@ -0,0 +1,220 @@
+package visisql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var errOrderingNotUnique = errors.New("paginated queries must be ordered by a unique column")
+
+// SelectOption configures a SelectService, see also BuildOption.
+type SelectOption interface {
+	applySelect(ss *selectService)
+}
+
+// TransactionOption configures a TransactionService, see BuildOption.
+type TransactionOption interface {
+	applyTransaction(ts *transactionService)
+}
+
+type selectOption func(ss *selectService)
+
+func (o selectOption) applySelect(ss *selectService) {
+	o(ss)
+}
+
+// BuildOption configures how predicates and expressions are built, by a SelectService
+// or a TransactionService.
+type BuildOption func(cfg *buildConfig)
+
+func (o BuildOption) applySelect(ss *selectService) {
+	o(ss.cfg)
+}
+
+func (o BuildOption) applyTransaction(ts *transactionService) {
+	o(ts.cfg)
+}
+
+// buildConfig holds the settings of a service used to build predicates and expressions.
+type buildConfig struct {
+	matchings    map[string]Matching
+	unaccentFunc string
+	funcs        map[string]bool
+}
+
+var defaultBuildConfig = newBuildConfig()
+
+func newBuildConfig() *buildConfig {
+	return &buildConfig{matchings: make(map[string]Matching), unaccentFunc: "unaccent", funcs: make(map[string]bool)}
+}
+
+func (cfg *buildConfig) allowsFunc(name string) bool {
+	name = strings.ToLower(name)
+	return allowedFuncs[name] || cfg.funcs[name]
+}
+
+// WithTieBreaker appends an ascending order on column, e.g. a primary key, to paginated queries
+// which are not already ordered by a unique column, so that pages are deterministic.
+func WithTieBreaker(column string) SelectOption {
+	return selectOption(func(ss *selectService) {
+		ss.tieBreaker = column
+		ss.uniqueColumns[column] = true
+	})
+}
+
+// WithStrictOrdering rejects paginated queries which are not ordered by the tie-breaker
+// or one of uniqueColumns, once the tie-breaker has been appended if possible.
+func WithStrictOrdering(uniqueColumns ...string) SelectOption {
+	return selectOption(func(ss *selectService) {
+		ss.strictOrdering = true
+		for _, c := range uniqueColumns {
+			ss.uniqueColumns[c] = true
+		}
+	})
+}
+
+// WithDefaultLimit paginates searches without pagination or limit with limit rows per page.
+func WithDefaultLimit(limit int) SelectOption {
+	return selectOption(func(ss *selectService) {
+		ss.defaultLimit = limit
+	})
+}
+
+// WithMaxLimit caps the rows per page of searches, including those without pagination or limit.
+func WithMaxLimit(limit int) SelectOption {
+	return selectOption(func(ss *selectService) {
+		ss.maxLimit = limit
+	})
+}
+
+// WithDefaultMatching sets the matching used by predicates on field when they do not have one.
+func WithDefaultMatching(field string, matching Matching) BuildOption {
+	return func(cfg *buildConfig) {
+		if matching == "" {
+			delete(cfg.matchings, field)
+			return
+		}
+
+		cfg.matchings[field] = matching
+	}
+}
+
+// WithUnaccentFunc sets the function used for accent insensitive matching, e.g. an immutable
+// wrapper of unaccent used by an expression index. The function is added to the allowed functions.
+func WithUnaccentFunc(name string) BuildOption {
+	return func(cfg *buildConfig) {
+		cfg.funcs[strings.ToLower(name)] = true
+		cfg.unaccentFunc = name
+	}
+}
+
+// WithAllowedFuncs adds function names to the built-in allow-list used to validate
+// predicate functions and expressions.
+func WithAllowedFuncs(names ...string) BuildOption {
+	return func(cfg *buildConfig) {
+		for _, n := range names {
+			cfg.funcs[strings.ToLower(n)] = true
+		}
+	}
+}
+
+// prepare applies the limits and the stable order to a search, other queries are only
+// paginated when asked for.
+func (ss *selectService) prepare(q *SelectQuery) (*SelectQuery, error) {
+	return ss.stableOrder(ss.limit(q))
+}
+
+func (ss *selectService) limit(q *SelectQuery) *SelectQuery {
+	if ss.defaultLimit <= 0 && ss.maxLimit <= 0 {
+		return q
+	}
+
+	limit := 0
+	if q.Pagination != nil {
+		if q.Pagination.Limit < 0 || q.Pagination.PageSize < 0 {
+			return q
+		}
+
+		limit = q.Pagination.limit()
+	}
+
+	capped := limit
+	if capped == 0 {
+		capped = ss.defaultLimit
+	}
+
+	if ss.maxLimit > 0 && (capped <= 0 || capped > ss.maxLimit) {
+		capped = ss.maxLimit
+	}
+
+	if capped <= 0 || (q.Pagination != nil && capped == limit) {
+		return q
+	}
+
+	c := q.Clone()
+	if c.Pagination == nil {
+		c.Pagination = &Pagination{}
+	}
+	c.Pagination.setLimit(capped)
+
+	return c
+}
+
+func (ss *selectService) stableOrder(q *SelectQuery) (*SelectQuery, error) {
+	if q.Pagination == nil || ss.uniquelyOrdered(q) {
+		return q, nil
+	}
+
+	if ss.tieBreaker != "" && canOrderBy(q, ss.tieBreaker) {
+		c := q.Clone()
+		c.OrderBy = append(c.OrderBy, NewOrderBy(ss.tieBreaker, OrderAsc))
+
+		return c, nil
+	}
+
+	if ss.strictOrdering {
+		return nil, fmt.Errorf("visisql order by: %w", &QueryError{errOrderingNotUnique})
+	}
+
+	return q, nil
+}
+
+func (ss *selectService) uniquelyOrdered(q *SelectQuery) bool {
+	for _, o := range q.OrderBy {
+		if o.Kind == "" && ss.uniqueColumns[o.Field] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// canOrderBy reports if adding column to the order keeps the query valid, as distinct queries
+// must select it, distinct on expressions must stay the leftmost orders and grouped queries
+// must group by it.
+func canOrderBy(q *SelectQuery, column string) bool {
+	if q.Distinct && !contains(q.Fields, column) {
+		return false
+	}
+
+	if len(q.DistinctOn) > len(q.OrderBy) {
+		return false
+	}
+
+	if len(q.GroupBy) > 0 && !contains(q.GroupBy, column) {
+		return false
+	}
+
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,205 @@
+package visisql
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errDistinctAndDistinctOn = errors.New("distinct and distinct on can not be used together")
+var errDistinctOnOrderBy = errors.New("distinct on expressions must match the leftmost order by expressions")
+var errDistinctOnWithoutFields = errors.New("distinct on requires at least one field")
+
+type SelectQuery struct {
+	With       []*CommonTableExpression `json:"with,omitempty"`
+	Distinct   bool                     `json:"distinct,omitempty"`
+	DistinctOn []string                 `json:"distinctOn,omitempty"`
+	Fields     []string                 `json:"fields"`
+	Headlines  []*Headline              `json:"headlines,omitempty"`
+	From       string                   `json:"from"`
+	Joins      []*Join                  `json:"joins,omitempty"`
+	Predicates [][]*Predicate           `json:"predicates,omitempty"`
+	GroupBy    []string                 `json:"groupBy,omitempty"`
+	Having     [][]*Predicate           `json:"having,omitempty"`
+	OrderBy    []*OrderBy               `json:"orderBy,omitempty"`
+	Pagination *Pagination              `json:"pagination,omitempty"`
+}
+
+func NewSelectQuery(fields []string, from string) *SelectQuery {
+	return &SelectQuery{Fields: fields, From: from}
+}
+
+func (q *SelectQuery) WithCommonTableExpressions(ctes ...*CommonTableExpression) *SelectQuery {
+	q.With = append(q.With, ctes...)
+	return q
+}
+
+func (q *SelectQuery) WithDistinct() *SelectQuery {
+	q.Distinct = true
+	return q
+}
+
+func (q *SelectQuery) WithDistinctOn(fields ...string) *SelectQuery {
+	q.DistinctOn = append(q.DistinctOn, fields...)
+	return q
+}
+
+func (q *SelectQuery) WithFields(fields ...string) *SelectQuery {
+	q.Fields = append(q.Fields, fields...)
+	return q
+}
+
+func (q *SelectQuery) WithHeadlines(headlines ...*Headline) *SelectQuery {
+	q.Headlines = append(q.Headlines, headlines...)
+	return q
+}
+
+func (q *SelectQuery) WithJoins(joins ...*Join) *SelectQuery {
+	q.Joins = append(q.Joins, joins...)
+	return q
+}
+
+func (q *SelectQuery) WithPredicates(predicates ...[]*Predicate) *SelectQuery {
+	q.Predicates = append(q.Predicates, predicates...)
+	return q
+}
+
+func (q *SelectQuery) WithGroupBy(groupBy ...string) *SelectQuery {
+	q.GroupBy = append(q.GroupBy, groupBy...)
+	return q
+}
+
+func (q *SelectQuery) WithHaving(predicates ...[]*Predicate) *SelectQuery {
+	q.Having = append(q.Having, predicates...)
+	return q
+}
+
+func (q *SelectQuery) WithOrderBy(orderBy ...*OrderBy) *SelectQuery {
+	q.OrderBy = append(q.OrderBy, orderBy...)
+	return q
+}
+
+func (q *SelectQuery) WithPagination(pagination *Pagination) *SelectQuery {
+	q.Pagination = pagination
+	return q
+}
+
+func (q *SelectQuery) Clone() *SelectQuery {
+	c := &SelectQuery{
+		Distinct:   q.Distinct,
+		DistinctOn: append([]string(nil), q.DistinctOn...),
+		Fields:     append([]string(nil), q.Fields...),
+		From:       q.From,
+		GroupBy:    append([]string(nil), q.GroupBy...),
+	}
+
+	for _, cte := range q.With {
+		c.With = append(c.With, cte.clone())
+	}
+
+	for _, j := range q.Joins {
+		c.Joins = append(c.Joins, j.clone())
+	}
+
+	c.Predicates = clonePredicates(q.Predicates)
+	c.Having = clonePredicates(q.Having)
+
+	for _, h := range q.Headlines {
+		ch := *h
+		c.Headlines = append(c.Headlines, &ch)
+	}
+
+	for _, o := range q.OrderBy {
+		co := *o
+		co.Values = cloneValues(o.Values)
+		c.OrderBy = append(c.OrderBy, &co)
+	}
+
+	if q.Pagination != nil {
+		cp := *q.Pagination
+		c.Pagination = &cp
+	}
+
+	return c
+}
+
+func (q *SelectQuery) isDistinct() bool {
+	return q.Distinct || len(q.DistinctOn) > 0
+}
+
+func (q *SelectQuery) validateDistinctOn() error {
+	if len(q.DistinctOn) == 0 {
+		return nil
+	}
+
+	if q.Distinct {
+		return errDistinctAndDistinctOn
+	}
+
+	if len(q.Fields) == 0 {
+		return errDistinctOnWithoutFields
+	}
+
+	distinctOn := make(map[string]bool, len(q.DistinctOn))
+	for _, f := range q.DistinctOn {
+		distinctOn[f] = true
+	}
+
+	for i, o := range q.OrderBy {
+		if i >= len(q.DistinctOn) {
+			break
+		}
+
+		if !distinctOn[o.Field] {
+			return fmt.Errorf("%w, got %s at position %d", errDistinctOnOrderBy, o.Field, i+1)
+		}
+	}
+
+	return nil
+}
+
+func clonePredicates(predicates [][]*Predicate) [][]*Predicate {
+	if predicates == nil {
+		return nil
+	}
+
+	c := make([][]*Predicate, 0, len(predicates))
+	for _, pAnd := range predicates {
+		ors := make([]*Predicate, 0, len(pAnd))
+		for _, pOr := range pAnd {
+			cp := *pOr
+			cp.Values = cloneValues(pOr.Values)
+			cp.Funcs = append([]string(nil), pOr.Funcs...)
+			if pOr.Query != nil {
+				cp.Query = pOr.Query.Clone()
+			}
+
+			cp.Functions = nil
+			for _, f := range pOr.Functions {
+				cf := *f
+				cf.Args = cloneValues(f.Args)
+				cp.Functions = append(cp.Functions, &cf)
+			}
+
+			if pOr.Named != nil {
+				cp.Named = make(map[string]interface{}, len(pOr.Named))
+				for k, v := range pOr.Named {
+					cp.Named[k] = v
+				}
+			}
+
+			ors = append(ors, &cp)
+		}
+
+		c = append(c, ors)
+	}
+
+	return c
+}
+
+func cloneValues(values []interface{}) []interface{} {
+	if values == nil {
+		return nil
+	}
+
+	return append(make([]interface{}, 0, len(values)), values...)
+}
@@ -4,29 +4,58 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/huandu/go-sqlbuilder"
 	"github.com/jmoiron/sqlx"
 )
 
 type SelectService interface {
-	Build(fields []string, from string, joins []*Join, predicates [][]*Predicate, groupBy []string, orderBy []*OrderBy, pagination *Pagination) (string, []interface{}, error)
+	Build(q *SelectQuery) (string, []interface{}, error)
 	Query(query string, args []interface{}, v interface{}) error
 	QueryRow(query string, args []interface{}, v interface{}) error
-	Search(fields []string, from string, joins []*Join, predicates [][]*Predicate, groupBy []string, orderBy []*OrderBy, pagination *Pagination, v interface{}) (int64, int64, int64, error)
-	Get(fields []string, from string, joins []*Join, predicates [][]*Predicate, groupBy []string, v interface{}) error
+	Search(q *SelectQuery, v interface{}) (int64, int64, int64, error)
+	SearchPage(q *SelectQuery, v interface{}) (*SearchResult, error)
+	Get(q *SelectQuery, v interface{}) error
+}
+
+type SearchResult struct {
+	Count       int64
+	TotalCount  int64
+	PageCount   int64
+	Page        int64
+	HasNext     bool
+	HasPrevious bool
 }
 
 type selectService struct {
 	db *sqlx.DB
+
+	tieBreaker     string
+	strictOrdering bool
+	uniqueColumns  map[string]bool
+	defaultLimit   int
+	maxLimit       int
+
+	cfg *buildConfig
 }
 
-func NewSelectService(db *sqlx.DB) SelectService {
-	return &selectService{db: db}
+func NewSelectService(db *sqlx.DB, opts ...SelectOption) SelectService {
+	ss := &selectService{db: db, uniqueColumns: make(map[string]bool), cfg: newBuildConfig()}
+	for _, opt := range opts {
+		opt.applySelect(ss)
+	}
+
+	return ss
 }
 
-func (ss *selectService) Build(fields []string, from string, joins []*Join, predicates [][]*Predicate, groupBy []string, orderBy []*OrderBy, pagination *Pagination) (string, []interface{}, error) {
-	builder, err := ss.newBuilder(fields, from, joins, predicates, groupBy, orderBy, pagination)
+func (ss *selectService) Build(q *SelectQuery) (string, []interface{}, error) {
+	q, err := ss.stableOrder(q)
+	if err != nil {
+		return "", nil, err
+	}
+
+	builder, err := newQueryBuilder(q, ss.cfg)
 	if err != nil {
 		return "", nil, err
 	}
@@ -79,37 +108,45 @@ func (ss *selectService) QueryRow(query string, args []interface{}, v interface{
 	return nil
 }
 
-func (ss *selectService) Search(fields []string, from string, joins []*Join, predicates [][]*Predicate, groupBy []string, orderBy []*OrderBy, pagination *Pagination, v interface{}) (int64, int64, int64, error) {
-	builderRs, err := ss.newBuilder(fields, from, joins, predicates, groupBy, orderBy, pagination)
+func (ss *selectService) Search(q *SelectQuery, v interface{}) (int64, int64, int64, error) {
+	res, err := ss.SearchPage(q, v)
 	if err != nil {
 		return 0, 0, 0, err
 	}
 
+	return res.Count, res.TotalCount, res.PageCount, nil
+}
+
+func (ss *selectService) SearchPage(q *SelectQuery, v interface{}) (*SearchResult, error) {
+	q, err := ss.prepare(q)
+	if err != nil {
+		return nil, err
+	}
+
+	builderRs, err := newQueryBuilder(q, ss.cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	queryRs, argsRs := builderRs.Build()
 
 	if err := ss.Query(queryRs, argsRs, v); err != nil {
-		return 0, 0, 0, fmt.Errorf("visisql records: %w", err)
+		return nil, fmt.Errorf("visisql records: %w", err)
 	}
 
 	if reflect.ValueOf(v).Elem().IsNil() {
-		return 0, 0, 0, nil
+		return newSearchResult(q.Pagination, 0, 0), nil
 	}
 
-	if reflect.ValueOf(v).Elem().IsNil() {
-		return 0, 0, 0, nil
+	builderTc, err := newTotalCountBuilder(q, ss.cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	builderRs.Select("count(*) over () as total_count")
-
 	builderC := sqlbuilder.PostgreSQL.NewSelectBuilder()
 
-	pageCount := "1 as page_count"
-	if pagination != nil && pagination.Limit > 0 {
-		pageCount = fmt.Sprintf("ceil(total_count::decimal / %d)::integer as page_count", pagination.Limit)
-	}
-
-	builderC.Select("count(*) as count", "total_count", pageCount)
-	builderC.From(builderC.BuilderAs(builderRs, "results"))
+	builderC.Select("count(*) as count", "total_count")
+	builderC.From(builderC.BuilderAs(builderTc, "results"))
 	builderC.GroupBy("total_count")
 
 	queryC, argsC := builderC.Build()
@@ -117,18 +154,43 @@ func (ss *selectService) Search(fields []string, from string, joins []*Join, pre
 	var CountSql = struct {
 		Count      int64 `db:"count"`
 		TotalCount int64 `db:"total_count"`
-		PageCount  int64 `db:"page_count"`
 	}{}
 
 	if err = ss.QueryRow(queryC, argsC, &CountSql); err != nil && err != sql.ErrNoRows {
-		return 0, 0, 0, fmt.Errorf("visisql count: %w", err)
+		return nil, fmt.Errorf("visisql count: %w", err)
+	}
+
+	return newSearchResult(q.Pagination, CountSql.Count, CountSql.TotalCount), nil
+}
+
+// newSearchResult computes the page details of a search from its pagination, the number of
+// rows of the page and the number of rows of all pages.
+func newSearchResult(pagination *Pagination, count, totalCount int64) *SearchResult {
+	var offset, limit int64
+	if pagination != nil {
+		offset, limit = int64(pagination.offset()), int64(pagination.limit())
+	}
+
+	res := &SearchResult{
+		Count:       count,
+		TotalCount:  totalCount,
+		Page:        1,
+		HasNext:     offset+count < totalCount,
+		HasPrevious: offset > 0,
+	}
+
+	if limit > 0 {
+		res.Page = offset/limit + 1
+		res.PageCount = (totalCount + limit - 1) / limit
+	} else if totalCount > 0 {
+		res.PageCount = 1
 	}
 
-	return CountSql.Count, CountSql.TotalCount, CountSql.PageCount, nil
+	return res
 }
 
-func (ss *selectService) Get(fields []string, from string, joins []*Join, predicates [][]*Predicate, groupBy []string, v interface{}) error {
-	query, args, err := ss.Build(fields, from, joins, predicates, groupBy, nil, nil)
+func (ss *selectService) Get(q *SelectQuery, v interface{}) error {
+	query, args, err := ss.Build(q)
 	if err != nil {
 		return err
 	}
@@ -136,41 +198,121 @@ func (ss *selectService) Get(fields []string, from string, joins []*Join, predic
 	return ss.QueryRow(query, args, v)
 }
 
-func (ss *selectService) newBuilder(fields []string, from string, joins []*Join, predicates [][]*Predicate, groupBy []string, orderBy []*OrderBy, pagination *Pagination) (*sqlbuilder.SelectBuilder, error) {
+func newBuilder(q *SelectQuery, cfg *buildConfig) (*sqlbuilder.SelectBuilder, error) {
+	if err := q.validateDistinctOn(); err != nil {
+		return nil, fmt.Errorf("visisql distinct on: %w", &QueryError{err})
+	}
+
 	builder := sqlbuilder.PostgreSQL.NewSelectBuilder()
 
-	builder.Select(fields...)
-	builder.From(from)
+	fields := q.Fields
+	if len(q.DistinctOn) > 0 {
+		fields = append([]string{fmt.Sprintf("DISTINCT ON (%s) %s", strings.Join(q.DistinctOn, ", "), fields[0])}, fields[1:]...)
+	}
+
+	if q.Distinct {
+		builder.Distinct()
+	}
 
-	for _, j := range joins {
-		if j.option == InnerJoin {
-			builder.Join(j.table, j.on)
-		} else {
-			builder.JoinWithOption(sqlbuilder.JoinOption(j.option), j.table, j.on)
+	for _, h := range q.Headlines {
+		sH, err := h.toString(&builder.Cond)
+		if err != nil {
+			return nil, fmt.Errorf("visisql headline: %w", &QueryError{err})
 		}
+		fields = append(fields, sH)
+	}
+
+	builder.Select(fields...)
+	builder.From(q.From)
+
+	if err := addJoins(q.Joins, builder, cfg); err != nil {
+		return nil, err
 	}
 
-	sPs, err := predicatesToStrings(predicates, &builder.Cond)
+	sPs, err := predicatesToStrings(q.Predicates, &builder.Cond, cfg)
 	if err != nil {
 		return nil, err
 	}
 	builder.Where(sPs...)
 
-	builder.GroupBy(groupBy...)
+	builder.GroupBy(q.GroupBy...)
+
+	if len(q.Having) > 0 {
+		// the builder only writes HAVING after GROUP BY, the empty grouping set keeps the whole table as one group
+		if len(q.GroupBy) == 0 {
+			builder.GroupBy("()")
+		}
+
+		sHs, err := predicatesToStrings(q.Having, &builder.Cond, cfg)
+		if err != nil {
+			return nil, err
+		}
+		builder.Having(sHs...)
+	}
 
 	var ob []string
-	for _, o := range orderBy {
-		ob = append(ob, o.toString())
+	for _, o := range q.OrderBy {
+		sO, err := o.toString(&builder.Cond, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("visisql order by: %w", &QueryError{err})
+		}
+		ob = append(ob, sO)
 	}
 	builder.OrderBy(ob...)
 
-	if pagination != nil {
-		builder.Offset(pagination.Start)
+	if q.Pagination != nil {
+		if err := q.Pagination.validate(); err != nil {
+			return nil, fmt.Errorf("visisql pagination: %w", &QueryError{err})
+		}
+	}
+	paginate(builder, q.Pagination)
+
+	return builder, nil
+}
+
+func newQueryBuilder(q *SelectQuery, cfg *buildConfig) (sqlbuilder.Builder, error) {
+	builder, err := newBuilder(q, cfg)
+	if err != nil {
+		return nil, err
+	}
 
-		if pagination.Limit > 0 {
-			builder.Limit(pagination.Limit)
+	return withCommonTableExpressions(q.With, builder, cfg)
+}
+
+func newTotalCountBuilder(q *SelectQuery, cfg *buildConfig) (sqlbuilder.Builder, error) {
+	if !q.isDistinct() {
+		builder, err := newBuilder(q, cfg)
+		if err != nil {
+			return nil, err
 		}
+
+		return withCommonTableExpressions(q.With, builder.Select("count(*) over () as total_count"), cfg)
+	}
+
+	dq := q.Clone()
+	dq.Pagination = nil
+
+	builderD, err := newQueryBuilder(dq, cfg)
+	if err != nil {
+		return nil, err
 	}
 
+	builder := sqlbuilder.PostgreSQL.NewSelectBuilder()
+
+	builder.Select("count(*) over () as total_count")
+	builder.From(builder.BuilderAs(builderD, "distinct_results"))
+
+	paginate(builder, q.Pagination)
+
 	return builder, nil
 }
+
+func paginate(builder *sqlbuilder.SelectBuilder, pagination *Pagination) {
+	if pagination != nil {
+		builder.Offset(pagination.offset())
+
+		if pagination.limit() > 0 {
+			builder.Limit(pagination.limit())
+		}
+	}
+}
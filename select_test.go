@@ -0,0 +1,974 @@
+package visisql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuild(t *testing.T) {
+	type in struct {
+		query *SelectQuery
+	}
+
+	type out struct {
+		query string
+		args  []interface{}
+		err   error
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var tests = []*test{{
+		message: "fields and from only",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id", "c.name"}, "company c"),
+		},
+		out: &out{
+			query: "SELECT c.id, c.name FROM company c",
+			args:  nil,
+		},
+	}, {
+		message: "all clauses",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id", "c.name"}, "company c").
+				WithJoins(NewJoin(InnerJoin, "user u", "u.company_id = c.id"), NewJoin(LeftJoin, "address a", "a.company_id = c.id")).
+				WithPredicates([]*Predicate{
+					NewPredicate("c.name", OperatorLike, []interface{}{"Company%"}),
+				}, []*Predicate{
+					NewPredicate("u.id", OperatorEqual, []interface{}{1}),
+					NewPredicate("u.id", OperatorEqual, []interface{}{2}),
+				}).
+				WithGroupBy("c.id").
+				WithOrderBy(NewOrderBy("c.id", OrderAsc)).
+				WithPagination(NewPagination(10, 5)),
+		},
+		out: &out{
+			query: "SELECT c.id, c.name FROM company c JOIN user u ON u.company_id = c.id LEFT JOIN address a ON a.company_id = c.id WHERE ( c.name LIKE $1 ) AND ( u.id = $2 OR u.id = $3 ) GROUP BY c.id ORDER BY c.id ASC LIMIT 5 OFFSET 10",
+			args:  []interface{}{"Company%", 1, 2},
+		},
+	}, {
+		message: "having with aggregate predicates",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id", "count(u.id) as users"}, "company c").
+				WithJoins(NewJoin(InnerJoin, "user u", "u.company_id = c.id")).
+				WithPredicates([]*Predicate{
+					NewPredicate("c.name", OperatorLike, []interface{}{"Company%"}),
+				}).
+				WithGroupBy("c.id").
+				WithHaving([]*Predicate{
+					NewPredicate("count(u.id)", OperatorGreaterThan, []interface{}{5}),
+				}),
+		},
+		out: &out{
+			query: "SELECT c.id, count(u.id) as users FROM company c JOIN user u ON u.company_id = c.id WHERE ( c.name LIKE $1 ) GROUP BY c.id HAVING ( count(u.id) > $2 )",
+			args:  []interface{}{"Company%", 5},
+		},
+	}, {
+		message: "having without group by",
+		in: &in{
+			query: NewSelectQuery([]string{"count(*)"}, "company c").WithHaving([]*Predicate{
+				NewPredicate("count(*)", OperatorGreaterThan, []interface{}{5}),
+			}),
+		},
+		out: &out{
+			query: "SELECT count(*) FROM company c GROUP BY () HAVING ( count(*) > $1 )",
+			args:  []interface{}{5},
+		},
+	}, {
+		message: "distinct",
+		in: &in{
+			query: NewSelectQuery([]string{"c.name"}, "company c").WithDistinct(),
+		},
+		out: &out{
+			query: "SELECT DISTINCT c.name FROM company c",
+			args:  nil,
+		},
+	}, {
+		message: "distinct on leading order by",
+		in: &in{
+			query: NewSelectQuery([]string{"u.company_id", "u.id", "u.created_at"}, "user u").
+				WithDistinctOn("u.company_id").
+				WithOrderBy(NewOrderBy("u.company_id", OrderAsc), NewOrderBy("u.created_at", OrderDesc)),
+		},
+		out: &out{
+			query: "SELECT DISTINCT ON (u.company_id) u.company_id, u.id, u.created_at FROM user u ORDER BY u.company_id ASC, u.created_at DESC",
+			args:  nil,
+		},
+	}, {
+		message: "distinct on not leading order by",
+		in: &in{
+			query: NewSelectQuery([]string{"u.company_id", "u.id"}, "user u").
+				WithDistinctOn("u.company_id").
+				WithOrderBy(NewOrderBy("u.created_at", OrderDesc), NewOrderBy("u.company_id", OrderAsc)),
+		},
+		out: &out{
+			err: &QueryError{fmt.Errorf("%w, got u.created_at at position 1", errDistinctOnOrderBy)},
+		},
+	}, {
+		message: "distinct with distinct on",
+		in: &in{
+			query: NewSelectQuery([]string{"u.company_id"}, "user u").WithDistinct().WithDistinctOn("u.company_id"),
+		},
+		out: &out{
+			err: &QueryError{errDistinctAndDistinctOn},
+		},
+	}, {
+		message: "distinct on without fields",
+		in: &in{
+			query: NewSelectQuery(nil, "user u").WithDistinctOn("u.company_id"),
+		},
+		out: &out{
+			err: &QueryError{errDistinctOnWithoutFields},
+		},
+	}, {
+		message: "common table expression",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id", "c.name"}, "company c").
+				WithCommonTableExpressions(NewCommonTableExpression("active_user", NewSelectQuery([]string{"u.company_id"}, "user u").WithPredicates([]*Predicate{
+					NewPredicate("u.status", OperatorEqual, []interface{}{"active"}),
+				}))).
+				WithJoins(NewJoin(InnerJoin, "active_user au", "au.company_id = c.id")).
+				WithPredicates([]*Predicate{
+					NewPredicate("c.name", OperatorLike, []interface{}{"Company%"}),
+				}),
+		},
+		out: &out{
+			query: "WITH active_user AS (SELECT u.company_id FROM user u WHERE ( u.status = $1 )) SELECT c.id, c.name FROM company c JOIN active_user au ON au.company_id = c.id WHERE ( c.name LIKE $2 )",
+			args:  []interface{}{"active", "Company%"},
+		},
+	}, {
+		message: "recursive common table expression",
+		in: &in{
+			query: NewSelectQuery([]string{"t.id", "t.name"}, "tree t").
+				WithCommonTableExpressions(NewRecursiveCommonTableExpression("tree", []string{"id", "name"},
+					NewSelectQuery([]string{"c.id", "c.name"}, "company c").WithPredicates([]*Predicate{
+						NewPredicate("c.id", OperatorEqual, []interface{}{1}),
+					}),
+					NewSelectQuery([]string{"s.id", "s.name"}, "company s").
+						WithJoins(NewJoin(InnerJoin, "tree t", "s.parent_id = t.id")).
+						WithPredicates([]*Predicate{
+							NewPredicate("s.deleted_at", OperatorIsNull, nil),
+						}),
+				)).
+				WithPredicates([]*Predicate{
+					NewPredicate("t.id", OperatorIn, []interface{}{2, 3}),
+				}),
+		},
+		out: &out{
+			query: "WITH RECURSIVE tree (id, name) AS (SELECT c.id, c.name FROM company c WHERE ( c.id = $1 ) UNION ALL SELECT s.id, s.name FROM company s JOIN tree t ON s.parent_id = t.id WHERE ( s.deleted_at IS NULL )) SELECT t.id, t.name FROM tree t WHERE ( t.id IN ($2, $3) )",
+			args:  []interface{}{1, 2, 3},
+		},
+	}, {
+		message: "common table expression with invalid name",
+		in: &in{
+			query: NewSelectQuery([]string{"x.id"}, "x").
+				WithCommonTableExpressions(NewCommonTableExpression("x; drop table company", NewSelectQuery([]string{"id"}, "company"))),
+		},
+		out: &out{
+			err: &QueryError{fmt.Errorf("%w, got %q", errInvalidIdentifier, "x; drop table company")},
+		},
+	}, {
+		message: "in subquery",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithPredicates([]*Predicate{
+				NewPredicate("c.name", OperatorLike, []interface{}{"Company%"}),
+			}, []*Predicate{
+				NewSubQueryPredicate("c.id", OperatorIn, NewSelectQuery([]string{"u.company_id"}, "user u").WithPredicates([]*Predicate{
+					NewPredicate("u.role", OperatorEqual, []interface{}{"admin"}),
+				})),
+			}, []*Predicate{
+				NewPredicate("c.id", OperatorGreaterThan, []interface{}{10}),
+			}),
+		},
+		out: &out{
+			query: "SELECT c.id FROM company c WHERE ( c.name LIKE $1 ) AND ( c.id IN (SELECT u.company_id FROM user u WHERE ( u.role = $2 )) ) AND ( c.id > $3 )",
+			args:  []interface{}{"Company%", "admin", 10},
+		},
+	}, {
+		message: "correlated exists and not exists subqueries",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithPredicates([]*Predicate{
+				NewExistsPredicate(NewSelectQuery([]string{"1"}, "user u").WithPredicates([]*Predicate{
+					NewPredicate("u.company_id", OperatorEqual, []interface{}{Column("c.id")}),
+				}, []*Predicate{
+					NewPredicate("u.role", OperatorEqual, []interface{}{"admin"}),
+				})),
+			}, []*Predicate{
+				NewNotExistsPredicate(NewSelectQuery([]string{"1"}, "invoice i").WithPredicates([]*Predicate{
+					NewPredicate("i.company_id", OperatorEqual, []interface{}{Column("c.id")}),
+				}, []*Predicate{
+					NewPredicate("i.paid", OperatorEqual, []interface{}{false}),
+				})),
+			}),
+		},
+		out: &out{
+			query: "SELECT c.id FROM company c WHERE ( EXISTS (SELECT 1 FROM user u WHERE ( u.company_id = c.id ) AND ( u.role = $1 )) ) AND ( NOT EXISTS (SELECT 1 FROM invoice i WHERE ( i.company_id = c.id ) AND ( i.paid = $2 )) )",
+			args:  []interface{}{"admin", false},
+		},
+	}, {
+		message: "join on subquery",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id", "uc.users"}, "company c").
+				WithJoins(NewSubQueryJoin(InnerJoin, NewSelectQuery([]string{"u.company_id", "count(*) as users"}, "user u").
+					WithPredicates([]*Predicate{
+						NewPredicate("u.role", OperatorEqual, []interface{}{"admin"}),
+					}).
+					WithGroupBy("u.company_id"), "uc", "uc.company_id = c.id")).
+				WithPredicates([]*Predicate{
+					NewPredicate("c.name", OperatorLike, []interface{}{"Company%"}),
+				}),
+		},
+		out: &out{
+			query: "SELECT c.id, uc.users FROM company c JOIN (SELECT u.company_id, count(*) as users FROM user u WHERE ( u.role = $1 ) GROUP BY u.company_id) AS uc ON uc.company_id = c.id WHERE ( c.name LIKE $2 )",
+			args:  []interface{}{"admin", "Company%"},
+		},
+	}, {
+		message: "left join lateral",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id", "lu.id"}, "company c").
+				WithJoins(NewLateralJoin(LeftJoin, NewSelectQuery([]string{"u.id"}, "user u").
+					WithPredicates([]*Predicate{
+						NewPredicate("u.company_id", OperatorEqual, []interface{}{Column("c.id")}),
+					}, []*Predicate{
+						NewPredicate("u.role", OperatorEqual, []interface{}{"admin"}),
+					}).
+					WithOrderBy(NewOrderBy("u.created_at", OrderDesc)).
+					WithPagination(NewPagination(0, 3)), "lu", "true")),
+		},
+		out: &out{
+			query: "SELECT c.id, lu.id FROM company c LEFT JOIN LATERAL (SELECT u.id FROM user u WHERE ( u.company_id = c.id ) AND ( u.role = $1 ) ORDER BY u.created_at DESC LIMIT 3 OFFSET 0) AS lu ON true",
+			args:  []interface{}{"admin"},
+		},
+	}, {
+		message: "lateral join without subquery",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithJoins(&Join{Option: LeftJoin, Table: "user u", Lateral: true, On: "true"}),
+		},
+		out: &out{
+			err: &QueryError{errJoinLateralWithoutSubQuery},
+		},
+	}, {
+		message: "join with predicates",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id", "u.id"}, "company c").
+				WithJoins(NewPredicateJoin(LeftJoin, "user u", [][]*Predicate{{
+					NewPredicate("u.company_id", OperatorEqual, []interface{}{Column("c.id")}),
+				}, {
+					NewPredicate("u.role", OperatorEqual, []interface{}{"admin"}),
+				}})).
+				WithPredicates([]*Predicate{
+					NewPredicate("c.name", OperatorLike, []interface{}{"Company%"}),
+				}),
+		},
+		out: &out{
+			query: "SELECT c.id, u.id FROM company c LEFT JOIN user u ON ( u.company_id = c.id ) AND ( u.role = $1 ) WHERE ( c.name LIKE $2 )",
+			args:  []interface{}{"admin", "Company%"},
+		},
+	}, {
+		message: "using, full and cross joins",
+		in: &in{
+			query: NewSelectQuery([]string{"*"}, "company c").
+				WithJoins(
+					NewUsingJoin(InnerJoin, "company_settings cs", []string{"company_id"}),
+					NewJoin(FullJoin, "address a", "a.company_id = c.id"),
+					NewCrossJoin("currency cu"),
+				),
+		},
+		out: &out{
+			query: "SELECT * FROM company c JOIN company_settings cs USING (company_id) FULL OUTER JOIN address a ON a.company_id = c.id CROSS JOIN currency cu",
+			args:  nil,
+		},
+	}, {
+		message: "cross join with condition",
+		in: &in{
+			query: NewSelectQuery([]string{"*"}, "company c").WithJoins(NewJoin(CrossJoin, "currency cu", "true")),
+		},
+		out: &out{
+			err: &QueryError{errJoinCrossCondition},
+		},
+	}, {
+		message: "join with using and condition",
+		in: &in{
+			query: NewSelectQuery([]string{"*"}, "company c").WithJoins(&Join{Option: InnerJoin, Table: "company_settings cs", On: "true", Using: []string{"company_id"}}),
+		},
+		out: &out{
+			err: &QueryError{errJoinUsingCondition},
+		},
+	}, {
+		message: "join without condition",
+		in: &in{
+			query: NewSelectQuery([]string{"*"}, "company c").WithJoins(NewJoin(LeftJoin, "user u", "")),
+		},
+		out: &out{
+			err: &QueryError{errJoinCondition},
+		},
+	}, {
+		message: "in operator with array",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithPredicates([]*Predicate{
+				NewPredicate("c.id", OperatorIn, []interface{}{1, 2, 3}).AsArray(),
+			}),
+		},
+		out: &out{
+			query: "SELECT c.id FROM company c WHERE ( c.id = ANY($1) )",
+			args:  []interface{}{pq.Array([]interface{}{1, 2, 3})},
+		},
+	}, {
+		message: "jsonb containment",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithPredicates([]*Predicate{
+				NewPredicate("c.phones", OperatorJSONContains, []interface{}{[]string{"01.02.03.04.05"}}),
+			}),
+		},
+		out: &out{
+			query: "SELECT c.id FROM company c WHERE ( c.phones @> $1::jsonb )",
+			args:  []interface{}{`["01.02.03.04.05"]`},
+		},
+	}, {
+		message: "full text search with rank and headline",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").
+				WithHeadlines(NewHeadline("c.description", "english", "cloud storage", "headline")).
+				WithPredicates([]*Predicate{
+					NewFullTextPredicate("c.description", "english", "cloud storage"),
+					NewTSVectorPredicate("c.search_vector", "english", "cloud storage"),
+				}).
+				WithOrderBy(NewFullTextRankOrderBy("c.description", "english", "cloud storage", OrderDesc), NewOrderBy("c.id", OrderAsc)).
+				WithPagination(NewPagination(0, 10)),
+		},
+		out: &out{
+			query: "SELECT c.id, ts_headline('english', c.description, websearch_to_tsquery('english', $1)) AS headline FROM company c WHERE ( to_tsvector('english', c.description) @@ websearch_to_tsquery('english', $2) OR c.search_vector @@ websearch_to_tsquery('english', $3) ) ORDER BY ts_rank(to_tsvector('english', c.description), websearch_to_tsquery('english', $4)) DESC, c.id ASC LIMIT 10 OFFSET 0",
+			args:  []interface{}{"cloud storage", "cloud storage", "cloud storage", "cloud storage"},
+		},
+	}, {
+		message: "headline with invalid field",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").
+				WithHeadlines(NewHeadline("c.description, 'x') AS h, (select password from users", "", "cloud", "headline")),
+		},
+		out: &out{
+			err: &QueryError{fmt.Errorf("%w, got %q", errInvalidColumn, "c.description, 'x') AS h, (select password from users")},
+		},
+	}, {
+		message: "full text search with default config and stored vector rank",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").
+				WithPredicates([]*Predicate{
+					NewFullTextPredicate("c.name", "", "acme"),
+				}).
+				WithOrderBy(NewTSVectorRankOrderBy("c.search_vector", "", "acme", OrderDesc)),
+		},
+		out: &out{
+			query: "SELECT c.id FROM company c WHERE ( to_tsvector(c.name) @@ websearch_to_tsquery($1) ) ORDER BY ts_rank(c.search_vector, websearch_to_tsquery($2)) DESC",
+			args:  []interface{}{"acme", "acme"},
+		},
+	}, {
+		message: "full text search with invalid config",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").
+				WithPredicates([]*Predicate{
+					NewFullTextPredicate("c.name", "english', c.name) or true or to_tsvector('english", "acme"),
+				}),
+		},
+		out: &out{
+			err: &QueryError{fmt.Errorf("%w, got %q", errInvalidTextSearchConfig, "english', c.name) or true or to_tsvector('english")},
+		},
+	}, {
+		message: "trigram similarity with distance ordering",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").
+				WithPredicates([]*Predicate{
+					NewPredicate("c.name", OperatorSimilar, []interface{}{"acme"}),
+					NewPredicate("c.name", OperatorWordSimilar, []interface{}{"acme"}, "unaccent"),
+				}, []*Predicate{
+					NewPredicate("c.name", OperatorSimilar, []interface{}{"acme", 0.4}),
+					NewPredicate("c.name", OperatorWordSimilar, []interface{}{"acme", 0.6}),
+				}).
+				WithOrderBy(NewDistanceOrderBy("c.name", "acme", OrderAsc)).
+				WithPagination(NewPagination(0, 10)),
+		},
+		out: &out{
+			query: "SELECT c.id FROM company c WHERE ( c.name % $1 OR unaccent($2) <% unaccent(c.name) ) AND ( similarity(c.name, $3) >= $4 OR word_similarity($5, c.name) >= $6 ) ORDER BY c.name <-> $7 ASC LIMIT 10 OFFSET 0",
+			args:  []interface{}{"acme", "acme", "acme", 0.4, "acme", 0.6, "acme"},
+		},
+	}, {
+		message: "range operators",
+		in: &in{
+			query: NewSelectQuery([]string{"b.id"}, "booking b").
+				WithPredicates([]*Predicate{
+					NewPredicate("b.during", OperatorRangeOverlaps, []interface{}{NewRange(1, 2, RangeBoundsInclusiveExclusive)}),
+					NewPredicate("b.during", OperatorRangeAdjacent, []interface{}{NewRange(2, 3, RangeBoundsInclusiveExclusive)}),
+				}, []*Predicate{
+					NewPredicate("b.during", OperatorRangeContains, []interface{}{Range{Lower: 1, Upper: 2, Bounds: RangeBoundsInclusive}}),
+					NewPredicate("b.during", OperatorRangeContainedBy, []interface{}{NewRange(nil, 10, RangeBoundsExclusive)}),
+				}),
+		},
+		out: &out{
+			query: "SELECT b.id FROM booking b WHERE ( b.during && $1 OR b.during -|- $2 ) AND ( b.during @> $3 OR b.during <@ $4 )",
+			args: []interface{}{
+				NewRange(1, 2, RangeBoundsInclusiveExclusive),
+				NewRange(2, 3, RangeBoundsInclusiveExclusive),
+				Range{Lower: 1, Upper: 2, Bounds: RangeBoundsInclusive},
+				NewRange(nil, 10, RangeBoundsExclusive),
+			},
+		},
+	}, {
+		message: "range operator without range value",
+		in: &in{
+			query: NewSelectQuery([]string{"b.id"}, "booking b").
+				WithPredicates([]*Predicate{
+					NewPredicate("b.during", OperatorRangeContains, []interface{}{1}),
+				}),
+		},
+		out: &out{
+			err: &QueryError{errOperatorRange},
+		},
+	}, {
+		message: "geospatial predicates and distance ordering",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").
+				WithPredicates([]*Predicate{
+					NewPredicate("c.location", OperatorGeoDWithin, []interface{}{NewPoint(2.3522, 48.8566), 1000}),
+				}, []*Predicate{
+					NewPredicate("c.location", OperatorGeoIntersects, []interface{}{NewPolygon(NewPoint(2, 48), NewPoint(2, 49), NewPoint(3, 49))}),
+					NewPredicate("c.area", OperatorGeoContains, []interface{}{NewPoint(2.3522, 48.8566)}),
+				}).
+				WithOrderBy(NewDistanceOrderBy("c.location", NewPoint(2.3522, 48.8566), OrderAsc)),
+		},
+		out: &out{
+			query: "SELECT c.id FROM company c WHERE ( ST_DWithin(c.location, $1, $2) ) AND ( ST_Intersects(c.location, $3) OR ST_Contains(c.area, $4) ) ORDER BY c.location <-> $5 ASC",
+			args: []interface{}{
+				NewPoint(2.3522, 48.8566),
+				1000,
+				NewPolygon(NewPoint(2, 48), NewPoint(2, 49), NewPoint(3, 49)),
+				NewPoint(2.3522, 48.8566),
+				NewPoint(2.3522, 48.8566),
+			},
+		},
+	}, {
+		message: "geospatial predicate without geometry",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").
+				WithPredicates([]*Predicate{
+					NewPredicate("c.location", OperatorGeoIntersects, []interface{}{"POINT(0 0)"}),
+				}),
+		},
+		out: &out{
+			err: &QueryError{errOperatorGeo},
+		},
+	}, {
+		message: "geospatial predicate with nil point",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").
+				WithPredicates([]*Predicate{
+					NewPredicate("c.location", OperatorGeoDWithin, []interface{}{(*Point)(nil), 1000}),
+				}),
+		},
+		out: &out{
+			err: &QueryError{errOperatorGeo},
+		},
+	}, {
+		message: "invalid predicate",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithPredicates([]*Predicate{
+				NewPredicate("c.id", OperatorEqual, []interface{}{1, 2}),
+			}),
+		},
+		out: &out{
+			err: &QueryError{errOperatorEqual},
+		},
+	}}
+
+	for _, test := range tests {
+		query, args, err := NewSelectService(nil).Build(test.in.query)
+
+		if test.out.err != nil {
+			var qe *QueryError
+
+			assert.True(t, errors.As(err, &qe), test.message)
+			assert.Equal(t, test.out.err, qe, test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+		}
+
+		assert.Equal(t, test.out.query, query, test.message)
+		assert.Equal(t, test.out.args, args, test.message)
+	}
+}
+
+func TestSelectQueryClone(t *testing.T) {
+	predicate := NewPredicate("c.id", OperatorEqual, []interface{}{1})
+	predicate.Funcs = []string{"lower"}
+	predicate.Named = map[string]interface{}{"id": 1}
+
+	base := NewSelectQuery([]string{"c.id"}, "company c").
+		WithPredicates([]*Predicate{predicate}).
+		WithOrderBy(NewPositionOrderBy("c.status", []interface{}{"a", "b"}, OrderAsc)).
+		WithPagination(NewPagination(0, 10))
+
+	clone := base.Clone().
+		WithFields("c.name").
+		WithPredicates([]*Predicate{NewPredicate("c.name", OperatorLike, []interface{}{"C%"})})
+	clone.Predicates[0][0].Field = "c.other_id"
+	clone.Predicates[0][0].Values[0] = 2
+	clone.Predicates[0][0].Funcs[0] = "upper"
+	clone.Predicates[0][0].Named["id"] = 2
+	clone.OrderBy[0].Values[0] = "c"
+	clone.Pagination.Limit = 20
+
+	assert.Equal(t, []string{"c.id"}, base.Fields)
+	assert.Len(t, base.Predicates, 1)
+	assert.Equal(t, "c.id", base.Predicates[0][0].Field)
+	assert.Equal(t, []interface{}{1}, base.Predicates[0][0].Values)
+	assert.Equal(t, []string{"lower"}, base.Predicates[0][0].Funcs)
+	assert.Equal(t, map[string]interface{}{"id": 1}, base.Predicates[0][0].Named)
+	assert.Equal(t, []interface{}{"a", "b"}, base.OrderBy[0].Values)
+	assert.Equal(t, 10, base.Pagination.Limit)
+
+	assert.Equal(t, []string{"c.id", "c.name"}, clone.Fields)
+	assert.Len(t, clone.Predicates, 2)
+}
+
+func TestSelectQueryJSON(t *testing.T) {
+	query := NewSelectQuery([]string{"c.id"}, "company c").
+		WithJoins(NewJoin(LeftJoin, "user u", "u.company_id = c.id")).
+		WithOrderBy(NewOrderBy("c.id", OrderDesc)).
+		WithPagination(NewPagination(0, 10))
+
+	b, err := json.Marshal(query)
+	assert.Nil(t, err)
+
+	var decoded SelectQuery
+	assert.Nil(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, query, &decoded)
+
+	var predicate Predicate
+	assert.Nil(t, json.Unmarshal([]byte(`{"operator":"IN","field":"c.id","query":{"fields":["1"],"from":"pg_user where usesuper"}}`), &predicate))
+	assert.Nil(t, predicate.Query)
+}
+
+func TestNewTotalCountBuilder(t *testing.T) {
+	type in struct {
+		query *SelectQuery
+	}
+
+	type out struct {
+		query string
+		args  []interface{}
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var tests = []*test{{
+		message: "without distinct",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").
+				WithPredicates([]*Predicate{NewPredicate("c.id", OperatorGreaterThan, []interface{}{1})}).
+				WithOrderBy(NewOrderBy("c.id", OrderAsc)).
+				WithPagination(NewPagination(0, 10)),
+		},
+		out: &out{
+			query: "SELECT count(*) over () as total_count FROM company c WHERE ( c.id > $1 ) ORDER BY c.id ASC LIMIT 10 OFFSET 0",
+			args:  []interface{}{1},
+		},
+	}, {
+		message: "with distinct on",
+		in: &in{
+			query: NewSelectQuery([]string{"u.company_id", "u.id"}, "user u").
+				WithDistinctOn("u.company_id").
+				WithPredicates([]*Predicate{NewPredicate("u.id", OperatorGreaterThan, []interface{}{1})}).
+				WithOrderBy(NewOrderBy("u.company_id", OrderAsc)).
+				WithPagination(NewPagination(0, 10)),
+		},
+		out: &out{
+			query: "SELECT count(*) over () as total_count FROM (SELECT DISTINCT ON (u.company_id) u.company_id, u.id FROM user u WHERE ( u.id > $1 ) ORDER BY u.company_id ASC) AS distinct_results LIMIT 10 OFFSET 0",
+			args:  []interface{}{1},
+		},
+	}}
+
+	for _, test := range tests {
+		builder, err := newTotalCountBuilder(test.in.query, defaultBuildConfig)
+		assert.Nil(t, err, test.message)
+
+		query, args := builder.Build()
+
+		assert.Equal(t, test.out.query, query, test.message)
+		assert.Equal(t, test.out.args, args, test.message)
+	}
+}
+
+func TestBuildStableOrder(t *testing.T) {
+	type in struct {
+		opts  []SelectOption
+		query *SelectQuery
+	}
+
+	type out struct {
+		query string
+		err   error
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var tests = []*test{{
+		message: "tie breaker appended to paginated query",
+		in: &in{
+			opts:  []SelectOption{WithTieBreaker("c.id")},
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithOrderBy(NewOrderBy("c.name", OrderAsc)).WithPagination(NewPagination(0, 10)),
+		},
+		out: &out{query: "SELECT c.id FROM company c ORDER BY c.name ASC, c.id ASC LIMIT 10 OFFSET 0"},
+	}, {
+		message: "tie breaker not appended without pagination",
+		in: &in{
+			opts:  []SelectOption{WithTieBreaker("c.id")},
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithOrderBy(NewOrderBy("c.name", OrderAsc)),
+		},
+		out: &out{query: "SELECT c.id FROM company c ORDER BY c.name ASC"},
+	}, {
+		message: "tie breaker not appended when ordered by unique column",
+		in: &in{
+			opts:  []SelectOption{WithTieBreaker("c.id"), WithStrictOrdering("c.siret")},
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithOrderBy(NewOrderBy("c.siret", OrderDesc)).WithPagination(NewPagination(0, 10)),
+		},
+		out: &out{query: "SELECT c.id FROM company c ORDER BY c.siret DESC LIMIT 10 OFFSET 0"},
+	}, {
+		message: "tie breaker not selected by distinct query in strict mode",
+		in: &in{
+			opts:  []SelectOption{WithTieBreaker("c.id"), WithStrictOrdering()},
+			query: NewSelectQuery([]string{"c.name"}, "company c").WithDistinct().WithPagination(NewPagination(0, 10)),
+		},
+		out: &out{err: &QueryError{errOrderingNotUnique}},
+	}, {
+		message: "tie breaker not appended before distinct on expressions",
+		in: &in{
+			opts:  []SelectOption{WithTieBreaker("u.id")},
+			query: NewSelectQuery([]string{"u.id"}, "user u").WithDistinctOn("u.company_id").WithPagination(NewPagination(0, 10)),
+		},
+		out: &out{query: "SELECT DISTINCT ON (u.company_id) u.id FROM user u LIMIT 10 OFFSET 0"},
+	}, {
+		message: "tie breaker appended after distinct on expressions",
+		in: &in{
+			opts:  []SelectOption{WithTieBreaker("u.id")},
+			query: NewSelectQuery([]string{"u.id"}, "user u").WithDistinctOn("u.company_id").WithOrderBy(NewOrderBy("u.company_id", OrderAsc)).WithPagination(NewPagination(0, 10)),
+		},
+		out: &out{query: "SELECT DISTINCT ON (u.company_id) u.id FROM user u ORDER BY u.company_id ASC, u.id ASC LIMIT 10 OFFSET 0"},
+	}, {
+		message: "strict mode without unique order",
+		in: &in{
+			opts:  []SelectOption{WithStrictOrdering("c.id")},
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithOrderBy(NewFullTextRankOrderBy("c.id", "", "acme", OrderDesc)).WithPagination(NewPagination(0, 10)),
+		},
+		out: &out{err: &QueryError{errOrderingNotUnique}},
+	}}
+
+	for _, test := range tests {
+		orderBy := test.in.query.OrderBy
+
+		query, _, err := NewSelectService(nil, test.in.opts...).Build(test.in.query)
+
+		if test.out.err != nil {
+			var qe *QueryError
+
+			assert.True(t, errors.As(err, &qe), test.message)
+			assert.Equal(t, test.out.err, qe, test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+		}
+
+		assert.Equal(t, test.out.query, query, test.message)
+		assert.Equal(t, orderBy, test.in.query.OrderBy, test.message)
+	}
+}
+
+func TestBuildConfig(t *testing.T) {
+	type in struct {
+		opts  []SelectOption
+		query *SelectQuery
+	}
+
+	type out struct {
+		query string
+		err   error
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var tests = []*test{{
+		message: "default matching with unaccent func",
+		in: &in{
+			opts: []SelectOption{WithDefaultMatching("c.name", MatchingInsensitive), WithUnaccentFunc("f_unaccent")},
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithPredicates([]*Predicate{
+				NewPredicate("c.name", OperatorEqual, []interface{}{"Acme"}),
+			}),
+		},
+		out: &out{query: "SELECT c.id FROM company c WHERE ( lower(f_unaccent(c.name)) = lower(f_unaccent($1)) )"},
+	}, {
+		message: "default matching not shared between services",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithPredicates([]*Predicate{
+				NewPredicate("c.name", OperatorEqual, []interface{}{"Acme"}),
+			}),
+		},
+		out: &out{query: "SELECT c.id FROM company c WHERE ( c.name = $1 )"},
+	}, {
+		message: "allowed funcs",
+		in: &in{
+			opts:  []SelectOption{WithAllowedFuncs("similarity")},
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithOrderBy(NewExpressionOrderBy("similarity(c.name, c.alias)", OrderDesc)),
+		},
+		out: &out{query: "SELECT c.id FROM company c ORDER BY (similarity(c.name, c.alias)) DESC"},
+	}, {
+		message: "func not allowed",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithOrderBy(NewExpressionOrderBy("similarity(c.name, c.alias)", OrderDesc)),
+		},
+		out: &out{err: &QueryError{fmt.Errorf("%w, got %q", errExpressionFunc, "similarity")}},
+	}}
+
+	for _, test := range tests {
+		query, _, err := NewSelectService(nil, test.in.opts...).Build(test.in.query)
+
+		if test.out.err != nil {
+			var qe *QueryError
+
+			assert.True(t, errors.As(err, &qe), test.message)
+			assert.Equal(t, test.out.err, qe, test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+		}
+
+		assert.Equal(t, test.out.query, query, test.message)
+	}
+}
+
+func TestBuildPagination(t *testing.T) {
+	type in struct {
+		opts   []SelectOption
+		query  *SelectQuery
+		search bool
+	}
+
+	type out struct {
+		query string
+		err   error
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var tests = []*test{{
+		message: "page pagination",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithPagination(NewPagePagination(3, 20)),
+		},
+		out: &out{query: "SELECT c.id FROM company c LIMIT 20 OFFSET 40"},
+	}, {
+		message: "default limit without pagination",
+		in: &in{
+			opts:   []SelectOption{WithDefaultLimit(50), WithMaxLimit(100)},
+			query:  NewSelectQuery([]string{"c.id"}, "company c"),
+			search: true,
+		},
+		out: &out{query: "SELECT c.id FROM company c LIMIT 50 OFFSET 0"},
+	}, {
+		message: "default limit with unlimited page",
+		in: &in{
+			opts:   []SelectOption{WithDefaultLimit(50)},
+			query:  NewSelectQuery([]string{"c.id"}, "company c").WithPagination(NewPagination(10, 0)),
+			search: true,
+		},
+		out: &out{query: "SELECT c.id FROM company c LIMIT 50 OFFSET 10"},
+	}, {
+		message: "max limit",
+		in: &in{
+			opts:   []SelectOption{WithMaxLimit(100)},
+			query:  NewSelectQuery([]string{"c.id"}, "company c").WithPagination(NewPagePagination(2, 1000)),
+			search: true,
+		},
+		out: &out{query: "SELECT c.id FROM company c LIMIT 100 OFFSET 100"},
+	}, {
+		message: "limit under max",
+		in: &in{
+			opts:   []SelectOption{WithMaxLimit(100)},
+			query:  NewSelectQuery([]string{"c.id"}, "company c").WithPagination(NewPagination(0, 10)),
+			search: true,
+		},
+		out: &out{query: "SELECT c.id FROM company c LIMIT 10 OFFSET 0"},
+	}, {
+		message: "limits not applied outside searches",
+		in: &in{
+			opts:  []SelectOption{WithDefaultLimit(50), WithMaxLimit(100)},
+			query: NewSelectQuery([]string{"c.id"}, "company c"),
+		},
+		out: &out{query: "SELECT c.id FROM company c"},
+	}, {
+		message: "max limit and strict ordering without pagination",
+		in: &in{
+			opts:  []SelectOption{WithMaxLimit(100), WithStrictOrdering("c.id")},
+			query: NewSelectQuery([]string{"c.id"}, "company c"),
+		},
+		out: &out{query: "SELECT c.id FROM company c"},
+	}, {
+		message: "max limit and tie breaker without pagination",
+		in: &in{
+			opts:  []SelectOption{WithMaxLimit(100), WithTieBreaker("c.id")},
+			query: NewSelectQuery([]string{"c.id"}, "company c"),
+		},
+		out: &out{query: "SELECT c.id FROM company c"},
+	}, {
+		message: "max limit and tie breaker in search",
+		in: &in{
+			opts:   []SelectOption{WithMaxLimit(100), WithTieBreaker("c.id")},
+			query:  NewSelectQuery([]string{"c.id"}, "company c"),
+			search: true,
+		},
+		out: &out{query: "SELECT c.id FROM company c ORDER BY c.id ASC LIMIT 100 OFFSET 0"},
+	}, {
+		message: "max limit and strict ordering in search",
+		in: &in{
+			opts:   []SelectOption{WithMaxLimit(100), WithStrictOrdering("c.id")},
+			query:  NewSelectQuery([]string{"c.id"}, "company c"),
+			search: true,
+		},
+		out: &out{err: &QueryError{errOrderingNotUnique}},
+	}, {
+		message: "negative start",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithPagination(NewPagination(-10, 10)),
+		},
+		out: &out{err: &QueryError{errPaginationNegative}},
+	}, {
+		message: "negative limit with max limit",
+		in: &in{
+			opts:   []SelectOption{WithMaxLimit(100)},
+			query:  NewSelectQuery([]string{"c.id"}, "company c").WithPagination(NewPagination(0, -1)),
+			search: true,
+		},
+		out: &out{err: &QueryError{errPaginationNegative}},
+	}, {
+		message: "start and page",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithPagination(&Pagination{Start: 10, Page: 2, PageSize: 10}),
+		},
+		out: &out{err: &QueryError{errPaginationMode}},
+	}, {
+		message: "page without page size",
+		in: &in{
+			query: NewSelectQuery([]string{"c.id"}, "company c").WithPagination(NewPagePagination(2, 0)),
+		},
+		out: &out{err: &QueryError{errPaginationPageSize}},
+	}}
+
+	for _, test := range tests {
+		ss := NewSelectService(nil, test.in.opts...)
+
+		query, _, err := ss.Build(test.in.query)
+		if test.in.search {
+			query, err = testSearchQuery(ss.(*selectService), test.in.query)
+		}
+
+		if test.out.err != nil {
+			var qe *QueryError
+
+			assert.True(t, errors.As(err, &qe), test.message)
+			assert.Equal(t, test.out.err, qe, test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+		}
+
+		assert.Equal(t, test.out.query, query, test.message)
+	}
+}
+
+// testSearchQuery builds the records query of SearchPage.
+func testSearchQuery(ss *selectService, q *SelectQuery) (string, error) {
+	q, err := ss.prepare(q)
+	if err != nil {
+		return "", err
+	}
+
+	builder, err := newQueryBuilder(q, ss.cfg)
+	if err != nil {
+		return "", err
+	}
+
+	query, _ := builder.Build()
+
+	return query, nil
+}
+
+func TestNewSearchResult(t *testing.T) {
+	type in struct {
+		pagination *Pagination
+		count      int64
+		totalCount int64
+	}
+
+	type out struct {
+		res *SearchResult
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var tests = []*test{{
+		message: "first page",
+		in:      &in{pagination: NewPagePagination(1, 10), count: 10, totalCount: 25},
+		out:     &out{res: &SearchResult{Count: 10, TotalCount: 25, PageCount: 3, Page: 1, HasNext: true}},
+	}, {
+		message: "middle page",
+		in:      &in{pagination: NewPagePagination(2, 10), count: 10, totalCount: 25},
+		out:     &out{res: &SearchResult{Count: 10, TotalCount: 25, PageCount: 3, Page: 2, HasNext: true, HasPrevious: true}},
+	}, {
+		message: "last page",
+		in:      &in{pagination: NewPagePagination(3, 10), count: 5, totalCount: 25},
+		out:     &out{res: &SearchResult{Count: 5, TotalCount: 25, PageCount: 3, Page: 3, HasPrevious: true}},
+	}, {
+		message: "page past the end",
+		in:      &in{pagination: NewPagePagination(4, 10)},
+		out:     &out{res: &SearchResult{Page: 4, HasPrevious: true}},
+	}, {
+		message: "start not multiple of limit",
+		in:      &in{pagination: NewPagination(15, 10), count: 10, totalCount: 40},
+		out:     &out{res: &SearchResult{Count: 10, TotalCount: 40, PageCount: 4, Page: 2, HasNext: true, HasPrevious: true}},
+	}, {
+		message: "without pagination",
+		in:      &in{count: 3, totalCount: 3},
+		out:     &out{res: &SearchResult{Count: 3, TotalCount: 3, PageCount: 1, Page: 1}},
+	}, {
+		message: "without limit",
+		in:      &in{pagination: NewPagination(2, 0), count: 1, totalCount: 3},
+		out:     &out{res: &SearchResult{Count: 1, TotalCount: 3, PageCount: 1, Page: 1, HasPrevious: true}},
+	}}
+
+	for _, test := range tests {
+		assert.Equal(t, test.out.res, newSearchResult(test.in.pagination, test.in.count, test.in.totalCount), test.message)
+	}
+}
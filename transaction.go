@@ -21,15 +21,26 @@ type TransactionService interface {
 
 type transactionService struct {
 	tx *sql.Tx
+
+	cfg *buildConfig
 }
 
-func NewTransactionService(db *sqlx.DB) (TransactionService, error) {
+func NewTransactionService(db *sqlx.DB, opts ...TransactionOption) (TransactionService, error) {
 	tx, err := db.Begin()
 	if err != nil {
 		return nil, err
 	}
 
-	return &transactionService{tx: tx}, nil
+	return newTransactionService(tx, opts...), nil
+}
+
+func newTransactionService(tx *sql.Tx, opts ...TransactionOption) *transactionService {
+	ts := &transactionService{tx: tx, cfg: newBuildConfig()}
+	for _, opt := range opts {
+		opt.applyTransaction(ts)
+	}
+
+	return ts
 }
 
 func (ts *transactionService) InsertOnConflictUpdate(into string, conflictOn []string, values map[string]interface{}, returning interface{}) (interface{}, error) {
@@ -172,7 +183,7 @@ func (ts *transactionService) Update(table string, set map[string]interface{}, p
 
 	builder.Set(str...)
 
-	sPs, err := predicatesToStrings(predicates, &builder.Cond)
+	sPs, err := predicatesToStrings(predicates, &builder.Cond, ts.cfg)
 	if err != nil {
 		return err
 	}
@@ -195,7 +206,7 @@ func (ts *transactionService) Delete(from string, predicates [][]*Predicate) err
 
 	builder.DeleteFrom(from)
 
-	sPs, err := predicatesToStrings(predicates, &builder.Cond)
+	sPs, err := predicatesToStrings(predicates, &builder.Cond, ts.cfg)
 	if err != nil {
 		return err
 	}
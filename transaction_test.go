@@ -0,0 +1,55 @@
+package visisql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionBuildConfig(t *testing.T) {
+	type in struct {
+		opts []TransactionOption
+	}
+
+	type out struct {
+		res []string
+		err error
+	}
+
+	type test struct {
+		message string
+		in      *in
+		out     *out
+	}
+
+	var tests = []*test{{
+		message: "func not allowed",
+		in:      &in{},
+		out:     &out{err: &QueryError{fmt.Errorf("%w, got %q", errExpressionFunc, "f_unaccent")}},
+	}, {
+		message: "allowed func",
+		in:      &in{opts: []TransactionOption{WithAllowedFuncs("f_unaccent")}},
+		out:     &out{res: []string{"( f_unaccent(c.name) = f_unaccent($0) )"}},
+	}}
+
+	for _, test := range tests {
+		ts := newTransactionService(nil, test.in.opts...)
+
+		predicates := [][]*Predicate{{NewPredicate("c.name", OperatorEqual, []interface{}{"Acme"}, "f_unaccent")}}
+		res, err := predicatesToStrings(predicates, &sqlbuilder.PostgreSQL.NewUpdateBuilder().Cond, ts.cfg)
+
+		if test.out.err != nil {
+			var qe *QueryError
+
+			assert.True(t, errors.As(err, &qe), test.message)
+			assert.Equal(t, test.out.err, qe, test.message)
+		} else {
+			assert.Nil(t, err, test.message)
+		}
+
+		assert.Equal(t, test.out.res, res, test.message)
+	}
+}